@@ -0,0 +1,56 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var auditMu sync.Mutex
+
+// AuditLine formats the audit record for one ping:
+//
+//	2026-02-25T12:00:00Z action=end job_key=abc123de outcome=ok
+//	2026-02-25T12:00:05Z action=ping job_key=abc123de outcome=NOT_FOUND http_status=404
+//
+// Fields are space separated and always appear in this order.
+func AuditLine(at time.Time, action string, jobKey string, err error) string {
+	line := fmt.Sprintf("%s action=%s job_key=%s", at.UTC().Format(time.RFC3339), action, jobKey)
+	if err == nil {
+		return line + " outcome=ok"
+	}
+
+	var apiErr *ApiError
+	var vErr *ValidationError
+	switch {
+	case errors.As(err, &apiErr):
+		line += " outcome=" + string(apiErr.Code)
+		if apiErr.HTTPStatus != nil {
+			line += fmt.Sprintf(" http_status=%d", *apiErr.HTTPStatus)
+		}
+	case errors.As(err, &vErr):
+		line += " outcome=" + string(CodeValidation)
+	default:
+		line += " outcome=" + string(CodeUnknown)
+	}
+	return line
+}
+
+func (c *PingClient) audit(cfg *clientConfig, action string, err error) {
+	if cfg.auditWriter == nil {
+		return
+	}
+	line := AuditLine(c.now(), action, c.jobKey, err) + "\n"
+
+	auditMu.Lock()
+	_, writeErr := cfg.auditWriter.Write([]byte(line))
+	auditMu.Unlock()
+	if writeErr != nil {
+		c.writeErrMu.Lock()
+		if c.writeErr == nil {
+			c.writeErr = &SdkError{Message: "failed to write audit line", Cause: writeErr}
+		}
+		c.writeErrMu.Unlock()
+	}
+}
@@ -0,0 +1,55 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAuditWriterRecordsEachPing(t *testing.T) {
+	var out bytes.Buffer
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{}`},
+			{status: 404, body: `{"message":"Job not found"}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{AuditWriter: &out})
+	client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+
+	_, _ = client.Start()
+	_, _ = client.Ping()
+
+	want := "2026-02-25T12:00:00Z action=start job_key=abc123de outcome=ok\n" +
+		"2026-02-25T12:00:00Z action=ping job_key=abc123de outcome=NOT_FOUND http_status=404\n"
+	if out.String() != want {
+		t.Fatalf("unexpected audit output:\n%s", out.String())
+	}
+}
+
+func TestAuditWriterIsSynchronized(t *testing.T) {
+	var out bytes.Buffer
+	client := newTestClient(t, &syncStubClient{}, &Options{AuditWriter: &out})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Ping()
+		}()
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 20 {
+		t.Fatalf("expected 20 audit lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, "action=ping job_key=abc123de outcome=ok") {
+			t.Fatalf("corrupted audit line: %q", line)
+		}
+	}
+}
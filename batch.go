@@ -0,0 +1,70 @@
+package cronbeatsgo
+
+import "context"
+
+// bufferProgress adds a progress update to the batch and sends the batch
+// once it holds ProgressBatchSize updates. Until then the result has Action
+// "buffered".
+func (c *PingClient) bufferProgress(ctx context.Context, cfg *clientConfig, seq *int, body map[string]any) (*PingSuccess, error) {
+	if seq != nil {
+		body["seq"] = *seq
+	}
+	c.batchMu.Lock()
+	c.batch = append(c.batch, body)
+	full := len(c.batch) >= cfg.progressBatch
+	c.batchMu.Unlock()
+
+	if !full {
+		return &PingSuccess{Ok: false, Action: "buffered", JobKey: c.jobKey}, nil
+	}
+	return c.flushProgress(ctx)
+}
+
+// FlushProgress sends the progress updates buffered under ProgressBatchSize
+// as one request, in the order they were made. It returns (nil, nil) when
+// nothing is buffered. Updates that were not delivered, because the request
+// failed, was throttled or the server accepted only the first "accepted"
+// of them, stay buffered ahead of newer ones for the next flush.
+func (c *PingClient) FlushProgress() (*PingSuccess, error) {
+	return c.flushProgress(context.Background())
+}
+
+func (c *PingClient) flushProgress(ctx context.Context) (*PingSuccess, error) {
+	c.batchFlushMu.Lock()
+	defer c.batchFlushMu.Unlock()
+
+	c.batchMu.Lock()
+	updates := c.batch
+	c.batch = nil
+	c.batchMu.Unlock()
+	if len(updates) == 0 {
+		return nil, nil
+	}
+
+	res, err := c.requestContext(ctx, "progress", route{action: "progress", batched: true}, map[string]any{"updates": updates})
+	sent := len(updates)
+	switch {
+	case err != nil || (res != nil && res.Action == "throttled"):
+		sent = 0
+	case res != nil && res.Raw != nil:
+		if raw, ok := res.Raw["accepted"]; ok {
+			if accepted := int(floatOrZero(raw)); accepted >= 0 && accepted < sent {
+				sent = accepted
+			}
+		}
+	}
+	if sent < len(updates) {
+		c.batchMu.Lock()
+		c.batch = append(updates[sent:len(updates):len(updates)], c.batch...)
+		c.batchMu.Unlock()
+	}
+	return res, err
+}
+
+// BufferedProgress returns how many progress updates are waiting for the
+// next flush.
+func (c *PingClient) BufferedProgress() int {
+	c.batchMu.Lock()
+	defer c.batchMu.Unlock()
+	return len(c.batch)
+}
@@ -0,0 +1,107 @@
+package cronbeatsgo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func batchMessages(t *testing.T, body string) []string {
+	t.Helper()
+	var decoded struct {
+		Updates []struct {
+			Message string `json:"message"`
+		} `json:"updates"`
+	}
+	if err := json.Unmarshal([]byte(body), &decoded); err != nil {
+		t.Fatalf("invalid batch body %q: %v", body, err)
+	}
+	var messages []string
+	for _, u := range decoded.Updates {
+		messages = append(messages, u.Message)
+	}
+	return messages
+}
+
+func TestProgressBatchSendsWhenFull(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressBatchSize: 3})
+
+	for i, msg := range []string{"a", "b"} {
+		res, err := client.Progress(nil, msg)
+		if err != nil || res.Action != "buffered" {
+			t.Fatalf("update %d: expected a buffered result, got %#v, %v", i, res, err)
+		}
+	}
+	if len(http.calls) != 0 || client.BufferedProgress() != 2 {
+		t.Fatalf("expected nothing sent yet, got %d calls", len(http.calls))
+	}
+	if _, err := client.Progress(7, "c"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 1 || http.calls[0].url != "https://cronbeats.io/ping/abc123de/progress" {
+		t.Fatalf("expected one batched request, got %#v", http.calls)
+	}
+	if got := batchMessages(t, http.calls[0].body); len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Fatalf("expected updates in order, got %v", got)
+	}
+	if want := `"message":"c","seq":7`; !strings.Contains(http.calls[0].body, want) {
+		t.Fatalf("expected the seq to travel with its update, got %s", http.calls[0].body)
+	}
+	if client.BufferedProgress() != 0 {
+		t.Fatalf("expected an empty buffer after the flush")
+	}
+}
+
+func TestFlushProgressRebuffersUnsentUpdates(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 3, responses: []stubResponse{
+		{status: 200, body: `{"accepted":1}`},
+		{status: 200, body: `{}`},
+	}}
+	client := newTestClient(t, http, &Options{ProgressBatchSize: 10, OfflineQueueSize: 5})
+
+	if res, err := client.FlushProgress(); res != nil || err != nil {
+		t.Fatalf("expected an empty flush to do nothing, got %v, %v", res, err)
+	}
+	_, _ = client.Progress(nil, "a")
+	_, _ = client.Progress(nil, "b")
+	if _, err := client.FlushProgress(); err == nil {
+		t.Fatal("expected network error")
+	}
+	if client.BufferedProgress() != 2 || len(client.PendingPings()) != 0 {
+		t.Fatalf("expected the batch back in the buffer and not in the offline queue")
+	}
+
+	_, _ = client.Progress(nil, "c")
+	if _, err := client.FlushProgress(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := batchMessages(t, http.calls[3].body); len(got) != 3 || got[0] != "a" || got[2] != "c" {
+		t.Fatalf("expected the retried batch in order, got %v", got)
+	}
+	if client.BufferedProgress() != 2 {
+		t.Fatalf("expected the updates past the accepted count to stay buffered, got %d", client.BufferedProgress())
+	}
+
+	_, _ = client.Progress(nil, "d")
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if got := batchMessages(t, http.calls[4].body); len(got) != 3 || got[0] != "b" || got[2] != "d" {
+		t.Fatalf("expected Close to flush the remaining updates in order, got %v", got)
+	}
+}
+
+func TestFlushProgressReadsAcceptedWithPreserveNumbers(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{"accepted":1}`}}}
+	client := newTestClient(t, http, &Options{ProgressBatchSize: 10, PreserveNumbers: true})
+
+	_, _ = client.Progress(nil, "a")
+	_, _ = client.Progress(nil, "b")
+	if _, err := client.FlushProgress(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.BufferedProgress() != 1 {
+		t.Fatalf("expected the update past the accepted count to stay buffered, got %d", client.BufferedProgress())
+	}
+}
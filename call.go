@@ -0,0 +1,76 @@
+package cronbeatsgo
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// CallOptions customize the requests made through a client returned by
+// PingClient.With.
+type CallOptions struct {
+	// LogFields are attached to every LogEvent emitted for the request,
+	// including retries and errors.
+	LogFields map[string]any
+	// Labels are attached to every AttemptMetric of the request. Each
+	// distinct value becomes its own series in most metrics backends, so
+	// keeping their cardinality bounded is up to the caller.
+	Labels map[string]string
+	// TraceID is sent in the Options.TraceIDHeader header and copied to
+	// PingSuccess.TraceID. It may be at most 256 characters.
+	TraceID string
+	// NoRetry sends each request once, whatever MaxRetries says.
+	NoRetry bool
+	// Rand, when set, replaces the client's shared random source for retry
+	// jitter, endpoint weighting and heartbeat jitter, so tests can seed
+	// each goroutine's client independently. A *rand.Rand is not safe for
+	// concurrent use; give each goroutine its own.
+	Rand *rand.Rand
+}
+
+// With returns a client that sends its requests with opts applied. The
+// returned client shares configuration, state and the offline queue with c.
+// Log fields and labels are merged over those already set on c; a TraceID
+// or Rand replaces c's.
+func (c *PingClient) With(opts CallOptions) *PingClient {
+	merged := CallOptions{
+		LogFields: mergeMaps(c.call.LogFields, opts.LogFields),
+		Labels:    mergeMaps(c.call.Labels, opts.Labels),
+		TraceID:   c.call.TraceID,
+		NoRetry:   c.call.NoRetry || opts.NoRetry,
+		Rand:      c.call.Rand,
+	}
+	if opts.TraceID != "" {
+		merged.TraceID = opts.TraceID
+	}
+	if opts.Rand != nil {
+		merged.Rand = opts.Rand
+	}
+	return &PingClient{clientCore: c.clientCore, call: merged}
+}
+
+const maxTraceIDLength = 256
+
+func validateTraceID(id string) error {
+	if len(id) > maxTraceIDLength {
+		return &ValidationError{Message: fmt.Sprintf("TraceID must be at most %d characters.", maxTraceIDLength)}
+	}
+	if strings.ContainsAny(id, "\r\n") {
+		return &ValidationError{Message: "TraceID must not contain line breaks."}
+	}
+	return nil
+}
+
+func mergeMaps[V any](base map[string]V, overrides map[string]V) map[string]V {
+	if len(base) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	merged := make(map[string]V, len(base)+len(overrides))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	return merged
+}
@@ -0,0 +1,125 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithAddsLogFieldsToEveryEntry(t *testing.T) {
+	var out bytes.Buffer
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 503, body: `{"message":"busy"}`},
+			{status: 500, body: `{"message":"down"}`},
+		},
+	}
+	client := newTestClient(t, http, (&Options{MaxRetries: 1}).WithJSONLogger(&out))
+	tenant := client.With(CallOptions{LogFields: map[string]any{"tenant": "acme", "event": "spoofed"}})
+	scoped := tenant.With(CallOptions{LogFields: map[string]any{"request_id": "r-1"}})
+
+	if _, err := scoped.Ping(); err == nil {
+		t.Fatalf("expected error")
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	wantEvents := []string{"request", "retry", "request", "error"}
+	if len(lines) != len(wantEvents) {
+		t.Fatalf("expected %d log lines, got %d:\n%s", len(wantEvents), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if entry["event"] != wantEvents[i] || entry["tenant"] != "acme" || entry["request_id"] != "r-1" {
+			t.Fatalf("unexpected entry %d: %v", i, entry)
+		}
+	}
+
+	if _, err := client.LastResult(); err == nil {
+		t.Fatalf("derived clients should share state with the parent")
+	}
+
+	out.Reset()
+	http.responses = []stubResponse{{status: 200, body: `{}`}}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "tenant") {
+		t.Fatalf("parent client should not carry derived log fields: %s", out.String())
+	}
+}
+
+func TestTraceIDIsSentAndRecorded(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, nil)
+
+	res, err := client.With(CallOptions{TraceID: "req-42"}).Ping()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.headers[0]["X-Trace-Id"] != "req-42" || res.TraceID != "req-42" {
+		t.Fatalf("expected trace ID in header and result, got %q / %q", http.headers[0]["X-Trace-Id"], res.TraceID)
+	}
+
+	custom := newTestClient(t, http, &Options{TraceIDHeader: "X-Correlation-Id"})
+	if _, err := custom.With(CallOptions{TraceID: "abc"}).Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.headers[1]["X-Correlation-Id"] != "abc" {
+		t.Fatalf("expected custom header, got %v", http.headers[1])
+	}
+
+	var vErr *ValidationError
+	if _, err := client.With(CallOptions{TraceID: strings.Repeat("x", 257)}).Ping(); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for long trace ID, got %v", err)
+	}
+	if _, err := client.With(CallOptions{TraceID: "a\r\nInjected: 1"}).Ping(); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for line breaks, got %v", err)
+	}
+	if len(http.headers) != 2 {
+		t.Fatalf("invalid trace IDs must not be sent")
+	}
+}
+
+func TestNoRetryMakesASingleAttempt(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 200, body: `{}`}}}
+	client := newTestClient(t, http, &Options{MaxRetries: 3})
+
+	if _, err := client.With(CallOptions{NoRetry: true}).Ping(); err == nil {
+		t.Fatalf("expected the 503 to be returned without retrying")
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected exactly one call, got %d", len(http.calls))
+	}
+	if _, err := client.Ping(); err != nil || len(http.calls) != 2 {
+		t.Fatalf("expected the parent client to keep retrying, got %v after %d calls", err, len(http.calls))
+	}
+}
+
+func TestRandMakesJitterReproducible(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBackoffMs: 10, RetryJitterMs: 1000})
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	run := func(seed int64) []time.Duration {
+		delays = nil
+		http.responses = []stubResponse{{status: 500, body: `{}`}, {status: 500, body: `{}`}, {status: 500, body: `{}`}, {status: 500, body: `{}`}}
+		_, _ = client.With(CallOptions{Rand: rand.New(rand.NewSource(seed))}).Ping()
+		return delays
+	}
+	first, second := run(7), run(7)
+	if len(first) != 3 || fmt.Sprint(first) != fmt.Sprint(second) {
+		t.Fatalf("expected identical delays for the same seed, got %v and %v", first, second)
+	}
+	if other := run(8); fmt.Sprint(other) == fmt.Sprint(first) {
+		t.Fatalf("expected a different seed to change the jitter, got %v", other)
+	}
+}
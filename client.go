@@ -1,28 +1,36 @@
 package cronbeatsgo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
+	"unicode/utf8"
 )
 
-type Options struct {
-	BaseURL        string
-	TimeoutMs      int
-	MaxRetries     int
-	RetryBackoffMs int
-	RetryJitterMs  int
-	UserAgent      string
-	HTTPClient     HttpClient
-}
-
 type ProgressOptions struct {
 	Seq     *int
 	Message string
+	// Current and Total report "Current of Total items" alongside Seq.
+	Current *int
+	Total   *int
+	// Fields are merged into the request body. They cannot replace
+	// message, current or total.
+	Fields map[string]any
 }
 
 type PingSuccess struct {
@@ -33,26 +41,110 @@ type PingSuccess struct {
 	ProcessingTimeMs float64
 	NextExpected     *string
 	Raw              map[string]any
+	// TraceID is the CallOptions.TraceID the request was sent with.
+	TraceID string
+}
+
+// RawInt returns the integer value of key in Raw. It accepts json.Number
+// (see Options.PreserveNumbers) and whole float64 values.
+func (r *PingSuccess) RawInt(key string) (int64, bool) {
+	switch v := r.Raw[key].(type) {
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	case float64:
+		if v != math.Trunc(v) || math.IsInf(v, 0) {
+			return 0, false
+		}
+		return int64(v), true
+	case int:
+		return int64(v), true
+	case int64:
+		return v, true
+	}
+	return 0, false
 }
 
 type PingClient struct {
-	baseURL        string
-	jobKey         string
-	timeoutMs      int
-	maxRetries     int
-	retryBackoffMs int
-	retryJitterMs  int
-	userAgent      string
-	httpClient     HttpClient
-	rng            *rand.Rand
-	sleep          func(time.Duration)
+	*clientCore
+	call CallOptions
+}
+
+// clientCore holds the state shared by a client and the clients derived from
+// it with With.
+type clientCore struct {
+	jobKey      string
+	mu          sync.RWMutex
+	options     Options
+	cfg         *clientConfig
+	defaultHTTP *NetHTTPClient
+	rngMu       sync.Mutex
+	rng         *rand.Rand
+	sleep       func(time.Duration)
+	now         func() time.Time
+	after       func(time.Duration) <-chan time.Time
+	dropped     atomic.Int64
+	inFlight    atomic.Int64
+	resources   resourceSampler
+	counters    clientCounters
+	firstOK     atomic.Bool
+	// initialOnce starts the InitialDelayMs timer on the first request;
+	// initialDone is closed once it has elapsed and gates every request.
+	initialOnce sync.Once
+	initialDone chan struct{}
+	parent      *clientCore
+	clientSeq   atomic.Uint64
+
+	stateMu     sync.Mutex
+	lastSuccess *PingSuccess
+	lastResult  *PingSuccess
+	lastErr     error
+	// runStartedAt is when the current run's Start was sent, zero outside
+	// a run.
+	runStartedAt time.Time
+
+	progressMu sync.Mutex
+	progressN  int
+
+	stepsMu sync.Mutex
+	steps   map[string]time.Time
+
+	dryMu    sync.Mutex
+	dryCalls []OutgoingRequest
+
+	writeErrMu sync.Mutex
+	writeErr   error
+
+	batchMu      sync.Mutex
+	batchFlushMu sync.Mutex
+	batch        []map[string]any
+
+	queueMu  sync.Mutex
+	flushMu  sync.Mutex
+	queue    []PendingPing
+	queueSeq uint64
+}
+
+type Timer struct {
+	startedAt time.Time
 }
 
 var jobKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9]{8}$`)
 
-func NewPingClient(jobKey string, opts *Options) (*PingClient, error) {
+var timestampLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05"}
+
+// ValidateJobKey reports whether jobKey is a well-formed job key, returning
+// the same *ValidationError NewPingClient would.
+func ValidateJobKey(jobKey string) error {
 	if !jobKeyRegex.MatchString(jobKey) {
-		return nil, &ValidationError{Message: "jobKey must be exactly 8 Base62 characters."}
+		return &ValidationError{Message: "jobKey must be exactly 8 Base62 characters."}
+	}
+	return nil
+}
+
+func NewPingClient(jobKey string, opts *Options) (*PingClient, error) {
+	if err := ValidateJobKey(jobKey); err != nil {
+		return nil, err
 	}
 
 	options := Options{}
@@ -60,49 +152,235 @@ func NewPingClient(jobKey string, opts *Options) (*PingClient, error) {
 		options = *opts
 	}
 
-	baseURL := strings.TrimRight(defaultString(options.BaseURL, "https://cronbeats.io"), "/")
-	timeoutMs := defaultInt(options.TimeoutMs, 5000)
-	maxRetries := defaultInt(options.MaxRetries, 2)
-	retryBackoffMs := defaultInt(options.RetryBackoffMs, 250)
-	retryJitterMs := defaultInt(options.RetryJitterMs, 100)
-	userAgent := defaultString(options.UserAgent, "cronbeats-go-sdk/0.1.0")
+	defaultHTTP := &NetHTTPClient{}
+	cfg, err := newClientConfig(options, defaultHTTP)
+	if err != nil {
+		return nil, err
+	}
 
-	httpClient := options.HTTPClient
-	if httpClient == nil {
-		httpClient = &NetHTTPClient{}
+	clock := options.Clock
+	if clock == nil {
+		clock = realClock{}
+	}
+	return &PingClient{clientCore: &clientCore{
+		jobKey:      jobKey,
+		options:     options,
+		cfg:         cfg,
+		defaultHTTP: defaultHTTP,
+		rng:         rand.New(rand.NewSource(clock.Now().UnixNano())),
+		sleep:       sleepOn(clock),
+		now:         clock.Now,
+		after:       clock.After,
+	}}, nil
+}
+
+// NewPingClientFromURL builds a client from a ping URL as shown on the
+// dashboard, e.g. "https://cronbeats.io/ping/YCrXzYbV". The base URL and job
+// key taken from pingURL override opts.BaseURL.
+func NewPingClientFromURL(pingURL string, opts *Options) (*PingClient, error) {
+	parsed, err := url.Parse(strings.TrimSpace(pingURL))
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, &ValidationError{Message: "ping URL must be an absolute URL."}
+	}
+
+	segments := strings.Split(strings.TrimRight(parsed.Path, "/"), "/")
+	if len(segments) < 2 || segments[len(segments)-2] != "ping" {
+		return nil, &ValidationError{Message: `ping URL path must end in "/ping/<jobKey>".`}
+	}
+	jobKey := segments[len(segments)-1]
+	if !jobKeyRegex.MatchString(jobKey) {
+		return nil, &ValidationError{Message: "ping URL job key must be exactly 8 Base62 characters."}
 	}
 
-	return &PingClient{
-		baseURL:        baseURL,
-		jobKey:         jobKey,
-		timeoutMs:      timeoutMs,
-		maxRetries:     maxRetries,
-		retryBackoffMs: retryBackoffMs,
-		retryJitterMs:  retryJitterMs,
-		userAgent:      userAgent,
-		httpClient:     httpClient,
-		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
-		sleep:          time.Sleep,
-	}, nil
+	options := Options{}
+	if opts != nil {
+		options = *opts
+	}
+	options.BaseURL = parsed.Scheme + "://" + parsed.Host + strings.Join(segments[:len(segments)-2], "/")
+	return NewPingClient(jobKey, &options)
 }
 
 func (c *PingClient) Ping() (*PingSuccess, error) {
-	return c.request("ping", fmt.Sprintf("/ping/%s", c.jobKey), nil)
+	return c.request("ping", route{action: "ping"}, nil)
+}
+
+// PingWithBody pings with body attached as JSON metadata. A "message" string
+// is truncated like a progress message.
+func (c *PingClient) PingWithBody(body map[string]any) (*PingSuccess, error) {
+	if len(body) == 0 {
+		return c.Ping()
+	}
+	if _, err := json.Marshal(body); err != nil {
+		return nil, &ValidationError{Message: "Ping body must be JSON-serializable: " + err.Error()}
+	}
+	out := make(map[string]any, len(body))
+	for key, value := range body {
+		out[key] = value
+	}
+	if msg, ok := out["message"].(string); ok {
+		fitted, err := c.config().fitMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		out["message"] = fitted
+	}
+	return c.request("ping", route{action: "ping"}, out)
+}
+
+// PingAt reports a ping that occurred at timestamp instead of now. The value
+// may be RFC 3339 or the server's "2006-01-02 15:04:05" UTC layout.
+func (c *PingClient) PingAt(timestamp string) (*PingSuccess, error) {
+	at, err := parseTimestamp(timestamp)
+	if err != nil {
+		return nil, &ValidationError{Message: "timestamp must be RFC 3339 or \"YYYY-MM-DD HH:MM:SS\"."}
+	}
+	if at.After(c.now().Add(c.config().clockSkew)) {
+		return nil, &ValidationError{Message: "timestamp must not be in the future."}
+	}
+	body := map[string]any{"timestamp": at.UTC().Format(time.RFC3339Nano)}
+	return c.request("ping", route{action: "ping"}, body)
+}
+
+// PingChecked pings and additionally fails with a *HealthError when the
+// response flags a problem: a non-success status, "overdue": true,
+// "healthy": false, or a next_expected time that has already passed.
+func (c *PingClient) PingChecked() error {
+	res, err := c.Ping()
+	if err != nil {
+		return err
+	}
+	if reason := c.unhealthyReason(res); reason != "" {
+		return &HealthError{Reason: reason, Result: res}
+	}
+	return nil
+}
+
+func (c *PingClient) unhealthyReason(res *PingSuccess) string {
+	if status, ok := res.Raw["status"].(string); ok && status != "" && !strings.EqualFold(status, "success") && !strings.EqualFold(status, "ok") {
+		return fmt.Sprintf("server reported status %q", status)
+	}
+	if overdue, ok := res.Raw["overdue"].(bool); ok && overdue {
+		return "server reported job as overdue"
+	}
+	if healthy, ok := res.Raw["healthy"].(bool); ok && !healthy {
+		return "server reported job as unhealthy"
+	}
+	if c.IsOverdue(res) {
+		return "next expected ping " + *res.NextExpected + " is already overdue"
+	}
+	return ""
+}
+
+// IsOverdue reports whether res carries a next_expected time that passed
+// more than ClockSkewTolerance ago, i.e. the job is already behind schedule.
+func (c *PingClient) IsOverdue(res *PingSuccess) bool {
+	if res == nil || res.NextExpected == nil {
+		return false
+	}
+	next, err := parseTimestamp(*res.NextExpected)
+	return err == nil && next.Add(c.config().clockSkew).Before(c.now())
 }
 
 func (c *PingClient) Start() (*PingSuccess, error) {
-	return c.request("start", fmt.Sprintf("/ping/%s/start", c.jobKey), nil)
+	return c.request("start", route{action: "start"}, nil)
+}
+
+// StartWithExpectedDuration starts a run and tells the server how long it is
+// expected to take, so a hung run can be flagged before the next schedule.
+func (c *PingClient) StartWithExpectedDuration(d time.Duration) (*PingSuccess, error) {
+	if d <= 0 {
+		return nil, &ValidationError{Message: "Expected duration must be positive."}
+	}
+	body := map[string]any{"expected_duration_ms": float64(d) / float64(time.Millisecond)}
+	return c.request("start", route{action: "start"}, body)
+}
+
+// maxScheduleSkew is how far in the future StartScheduled accepts a
+// scheduled time, to allow for clock drift between scheduler and worker.
+const maxScheduleSkew = 5 * time.Minute
+
+// StartScheduled starts a run that was due at scheduledAt, sending both the
+// scheduled and the actual start time so the server can report scheduling
+// lag.
+func (c *PingClient) StartScheduled(scheduledAt time.Time) (*PingSuccess, error) {
+	if scheduledAt.IsZero() {
+		return nil, &ValidationError{Message: "Scheduled time is required."}
+	}
+	startedAt := c.now()
+	if scheduledAt.Sub(startedAt) > maxScheduleSkew {
+		return nil, &ValidationError{Message: "Scheduled time must not be more than 5 minutes in the future."}
+	}
+	body := map[string]any{
+		"scheduled_at": scheduledAt.UTC().Format(time.RFC3339Nano),
+		"started_at":   startedAt.UTC().Format(time.RFC3339Nano),
+	}
+	return c.request("start", route{action: "start"}, body)
+}
+
+// StartWithSchedule starts a run and reports the cron expression the job is
+// scheduled with, so the server can flag a schedule that differs from the
+// dashboard's. The expression must be five fields of "*", values, ranges,
+// steps and lists (month and weekday names allowed) or a macro such as
+// "@daily"; anything else returns a *ValidationError without sending.
+func (c *PingClient) StartWithSchedule(cronExpr string) (*PingSuccess, error) {
+	schedule, err := normalizeCron(cronExpr)
+	if err != nil {
+		return nil, err
+	}
+	return c.request("start", route{action: "start"}, map[string]any{"schedule": schedule})
 }
 
 func (c *PingClient) End(status string) (*PingSuccess, error) {
-	statusValue := strings.ToLower(strings.TrimSpace(status))
-	if statusValue == "" {
-		statusValue = "success"
+	return c.end(status, nil)
+}
+
+// EndWithMessage ends the run with a closing summary, capped like a progress
+// message.
+func (c *PingClient) EndWithMessage(status string, message string) (*PingSuccess, error) {
+	if message == "" {
+		return c.end(status, nil)
+	}
+	fitted, err := c.config().fitMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	return c.end(status, map[string]any{"message": fitted})
+}
+
+// EndWithCounts ends the run with a breakdown of its outcomes, such as
+// succeeded, failed and skipped items. status is still sent as the overall
+// result.
+func (c *PingClient) EndWithCounts(status string, counts map[string]int) (*PingSuccess, error) {
+	if len(counts) == 0 {
+		return c.end(status, nil)
+	}
+	breakdown := make(map[string]any, len(counts))
+	for name, n := range counts {
+		if strings.TrimSpace(name) == "" {
+			return nil, &ValidationError{Message: "Count names must not be empty."}
+		}
+		if n < 0 {
+			return nil, &ValidationError{Message: fmt.Sprintf("Count %q must not be negative.", name)}
+		}
+		breakdown[name] = n
+	}
+	return c.end(status, map[string]any{"counts": breakdown})
+}
+
+func (c *PingClient) StartTimer() Timer {
+	return Timer{startedAt: c.now()}
+}
+
+func (c *PingClient) EndTimer(timer Timer, status string) (*PingSuccess, error) {
+	if timer.startedAt.IsZero() {
+		return nil, &ValidationError{Message: "Timer must be created with StartTimer."}
 	}
-	if statusValue != "success" && statusValue != "fail" {
-		return nil, &ValidationError{Message: `Status must be "success" or "fail".`}
+	elapsed := c.now().Sub(timer.startedAt)
+	if elapsed < 0 {
+		elapsed = 0
 	}
-	return c.request("end", fmt.Sprintf("/ping/%s/end/%s", c.jobKey, statusValue), nil)
+	body := map[string]any{"duration_ms": float64(elapsed) / float64(time.Millisecond)}
+	return c.end(status, body)
 }
 
 func (c *PingClient) Success() (*PingSuccess, error) {
@@ -113,7 +391,72 @@ func (c *PingClient) Fail() (*PingSuccess, error) {
 	return c.End("fail")
 }
 
+// Cancel ends the run as deliberately canceled, which the dashboard does not
+// count as a failure.
+func (c *PingClient) Cancel() (*PingSuccess, error) {
+	return c.End("canceled")
+}
+
+// Skip reports that a scheduled run deliberately did not happen, e.g.
+// because its preconditions were not met, so the server does not flag it
+// as missed. reason is optional and capped like a progress message.
+func (c *PingClient) Skip(reason string) (*PingSuccess, error) {
+	if strings.TrimSpace(reason) == "" {
+		return c.request("skip", route{action: "skip"}, nil)
+	}
+	fitted, err := c.config().fitMessage(reason)
+	if err != nil {
+		return nil, err
+	}
+	return c.request("skip", route{action: "skip"}, map[string]any{"reason": fitted})
+}
+
+func (c *PingClient) FailWithReason(reason string) (*PingSuccess, error) {
+	if strings.TrimSpace(reason) == "" {
+		return c.Fail()
+	}
+	fitted, err := c.config().fitMessage(reason)
+	if err != nil {
+		return nil, err
+	}
+	return c.end("fail", map[string]any{"reason": fitted})
+}
+
+// FailWithError ends the run as failed, recording err's message as the reason
+// and its Go type as "error_type".
+func (c *PingClient) FailWithError(err error) (*PingSuccess, error) {
+	if err == nil {
+		return c.Fail()
+	}
+	body := map[string]any{"error_type": fmt.Sprintf("%T", err)}
+	if reason := strings.TrimSpace(err.Error()); reason != "" {
+		fitted, err := c.config().fitMessage(reason)
+		if err != nil {
+			return nil, err
+		}
+		body["reason"] = fitted
+	}
+	return c.end("fail", body)
+}
+
 func (c *PingClient) Progress(input any, message ...string) (*PingSuccess, error) {
+	return c.progress(context.Background(), input, message...)
+}
+
+// ProgressContext is Progress bound to ctx. If ctx has a deadline that is
+// closer than Options.ProgressSkipThresholdMs, nothing is sent and the result
+// has Action "skipped", leaving the remaining time to the job itself.
+func (c *PingClient) ProgressContext(ctx context.Context, input any, message ...string) (*PingSuccess, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		threshold := time.Duration(c.config().progressSkipThreshold()) * time.Millisecond
+		if time.Until(deadline) < threshold {
+			return &PingSuccess{Ok: false, Action: "skipped", JobKey: c.jobKey}, nil
+		}
+	}
+	return c.progress(ctx, input, message...)
+}
+
+func (c *PingClient) progress(ctx context.Context, input any, message ...string) (*PingSuccess, error) {
 	msg := ""
 	if len(message) > 0 {
 		msg = message[0]
@@ -121,115 +464,663 @@ func (c *PingClient) Progress(input any, message ...string) (*PingSuccess, error
 
 	seq := -1
 	seqProvided := false
+	var opts *ProgressOptions
 
 	switch v := input.(type) {
 	case nil:
 	case int:
 		seq = v
 		seqProvided = true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil, &ValidationError{Message: "Progress seq must be a finite number."}
+		}
+		seq = int(math.Round(v))
+		seqProvided = true
 	case ProgressOptions:
-		if v.Seq != nil {
-			seq = *v.Seq
+		opts = &v
+	case *ProgressOptions:
+		opts = v
+	default:
+		return nil, &ValidationError{Message: "Progress input must be int, float64, ProgressOptions, or nil."}
+	}
+
+	if opts != nil {
+		if opts.Seq != nil {
+			seq = *opts.Seq
 			seqProvided = true
 		}
-		if strings.TrimSpace(v.Message) != "" || msg == "" {
-			msg = v.Message
-		}
-	case *ProgressOptions:
-		if v != nil {
-			if v.Seq != nil {
-				seq = *v.Seq
-				seqProvided = true
-			}
-			if strings.TrimSpace(v.Message) != "" || msg == "" {
-				msg = v.Message
-			}
+		if strings.TrimSpace(opts.Message) != "" || msg == "" {
+			msg = opts.Message
 		}
-	default:
-		return nil, &ValidationError{Message: "Progress input must be int, ProgressOptions, or nil."}
 	}
 
 	if seqProvided && seq < 0 {
 		return nil, &ValidationError{Message: "Progress seq must be a non-negative integer."}
 	}
+	if !seqProvided && strings.TrimSpace(msg) == "" && c.config().requireMessage {
+		return nil, &ValidationError{Message: "Progress message is required."}
+	}
+
+	msg, err := c.config().fitMessage(msg)
+	if err != nil {
+		return nil, err
+	}
 
-	if len(msg) > 255 {
-		msg = msg[:255]
+	body := map[string]any{}
+	if opts != nil {
+		for key, value := range opts.Fields {
+			body[key] = value
+		}
+		if opts.Current != nil && *opts.Current < 0 {
+			return nil, &ValidationError{Message: "Progress current must be a non-negative integer."}
+		}
+		if opts.Total != nil && *opts.Total < 0 {
+			return nil, &ValidationError{Message: "Progress total must be a non-negative integer."}
+		}
+		if opts.Current != nil && opts.Total != nil && *opts.Current > *opts.Total {
+			return nil, &ValidationError{Message: "Progress current must not exceed total."}
+		}
+		if opts.Current != nil {
+			body["current"] = *opts.Current
+		}
+		if opts.Total != nil {
+			body["total"] = *opts.Total
+		}
 	}
+	body["message"] = msg
 
-	body := map[string]any{"message": msg}
+	if cfg := c.config(); cfg.progressBatch > 1 {
+		if seqProvided {
+			return c.bufferProgress(ctx, cfg, &seq, body)
+		}
+		return c.bufferProgress(ctx, cfg, nil, body)
+	}
 	if seqProvided {
-		return c.request("progress", fmt.Sprintf("/ping/%s/progress/%d", c.jobKey, seq), body)
+		return c.requestContext(ctx, "progress", route{action: "progress", seq: &seq}, body)
+	}
+	return c.requestContext(ctx, "progress", route{action: "progress"}, body)
+}
+
+// Warmup sends a HEAD request to BaseURL so the connection is already open
+// when the first ping is sent. It is best-effort: any HTTP response counts as
+// success, and the returned error only explains why warming up failed.
+func (c *PingClient) Warmup(ctx context.Context) error {
+	cfg := c.config()
+	headers := map[string]string{"User-Agent": cfg.userAgent}
+	if cfg.dryRun {
+		c.recordDryRun(&OutgoingRequest{Method: "HEAD", URL: cfg.baseURL, Headers: headers})
+		return nil
+	}
+	_, err := sendRequest(withRequestInfo(ctx, c.jobKey, "warmup"), cfg.httpClient, "HEAD", cfg.baseURL, headers, nil, cfg.timeoutMs)
+	return err
+}
+
+// InFlight returns how many HTTP requests the client is currently waiting on.
+func (c *PingClient) InFlight() int {
+	return int(c.inFlight.Load())
+}
+
+// LastResult returns the outcome of the most recent request sent by the
+// client, or (nil, nil) if none has completed yet.
+func (c *PingClient) LastResult() (*PingSuccess, error) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	return c.lastResult, c.lastErr
+}
+
+// NextPing returns the next_expected time from the most recent successful
+// response.
+func (c *PingClient) NextPing() (*time.Time, error) {
+	c.stateMu.Lock()
+	last := c.lastSuccess
+	c.stateMu.Unlock()
+
+	if last == nil {
+		return nil, ErrNoResponse
+	}
+	if last.NextExpected == nil {
+		return nil, ErrNoNextExpected
+	}
+	next, err := parseTimestamp(*last.NextExpected)
+	if err != nil {
+		return nil, &SdkError{Message: "failed to parse next_expected", Cause: err}
+	}
+	return &next, nil
+}
+
+// ClientSeq returns the client_seq of the most recent request, or 0 if
+// Options.IncludeClientSeq is off or nothing has been sent.
+func (c *PingClient) ClientSeq() uint64 {
+	return c.clientSeq.Load()
+}
+
+// WouldRateLimit reports whether a ping sent now would be delayed, or with
+// ThrottleDrop discarded, by the client-side rate limiter. It does not use
+// up a token. The answer is advisory: other calls may take the token before
+// this caller's next ping.
+func (c *PingClient) WouldRateLimit() bool {
+	cfg := c.config().forAction("ping")
+	return cfg.limiter != nil && !cfg.limiter.available(c.now())
+}
+
+// DroppedPings reports how many calls ThrottleDrop has discarded.
+func (c *PingClient) DroppedPings() int64 {
+	return c.dropped.Load()
+}
+
+// ProgressIncrement advances the client's progress counter by delta and
+// reports the new total as the progress seq.
+func (c *PingClient) ProgressIncrement(delta int, message string) (*PingSuccess, error) {
+	if delta < 0 {
+		return nil, &ValidationError{Message: "Progress delta must be a non-negative integer."}
+	}
+	return c.Progress(c.advanceProgress(delta), message)
+}
+
+func (c *PingClient) advanceProgress(delta int) int {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.progressN += delta
+	return c.progressN
+}
+
+// ProgressFraction reports current of total items done, with the percentage
+// as the progress seq and, once Start has succeeded and current is above
+// zero, an "eta_ms" estimate of the time remaining extrapolated from the
+// time elapsed since Start. An optional note is sent as the message, fitted
+// like any progress message.
+func (c *PingClient) ProgressFraction(current, total int, note ...string) (*PingSuccess, error) {
+	if total <= 0 {
+		return nil, &ValidationError{Message: "Progress total must be a positive integer."}
+	}
+	if current < 0 || current > total {
+		return nil, &ValidationError{Message: "Progress current must be between 0 and total."}
+	}
+	percent := current * 100 / total
+	opts := ProgressOptions{Seq: &percent, Current: &current, Total: &total}
+	if len(note) > 0 {
+		opts.Message = note[0]
+	}
+
+	c.stateMu.Lock()
+	startedAt := c.runStartedAt
+	c.stateMu.Unlock()
+	if !startedAt.IsZero() && current > 0 {
+		elapsed := c.now().Sub(startedAt)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		remaining := float64(elapsed) * float64(total-current) / float64(current)
+		opts.Fields = map[string]any{"eta_ms": remaining / float64(time.Millisecond)}
+	}
+	return c.Progress(opts)
+}
+
+// SetProgressFloor raises the progress counter to n if it is lower, so a
+// restarted job continues the sequences of the run it resumes instead of
+// reporting lower ones. It never lowers the counter.
+func (c *PingClient) SetProgressFloor(n int) error {
+	if n < 0 {
+		return &ValidationError{Message: "Progress floor must be a non-negative integer."}
+	}
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.progressN = maxInt(c.progressN, n)
+	return nil
+}
+
+// CurrentProgress returns the counter advanced by ProgressIncrement.
+func (c *PingClient) CurrentProgress() int {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	return c.progressN
+}
+
+func (c *PingClient) end(status string, body map[string]any) (*PingSuccess, error) {
+	statusValue := strings.ToLower(strings.TrimSpace(status))
+	if statusValue == "" {
+		statusValue = c.config().defaultEndStatus
+		if statusValue == "" {
+			return nil, &ValidationError{Message: "Status is required."}
+		}
+	}
+	statusValue, err := normalizeEndStatus(statusValue)
+	if err != nil {
+		return nil, err
+	}
+	return c.request("end", route{action: "end/" + statusValue}, body)
+}
+
+// route identifies the endpoint of a request: the URL action segment, such
+// as "start" or "end/fail", and the progress sequence number if any. Batched
+// progress is kept in the progress buffer on failure rather than queued.
+type route struct {
+	action  string
+	seq     *int
+	batched bool
+}
+
+// DefaultURLBuilder builds the standard ping URLs, e.g.
+// "https://cronbeats.io/ping/<jobKey>/progress/3". action is "ping",
+// "start", "progress" or "end/<status>".
+func DefaultURLBuilder(baseURL string, jobKey string, action string, seq *int) string {
+	elems := []string{"ping", jobKey}
+	if action != "ping" {
+		elems = append(elems, action)
+	}
+	if seq != nil {
+		elems = append(elems, strconv.Itoa(*seq))
+	}
+	return joinURLPath(baseURL, elems...)
+}
+
+// joinURLPath appends elems to the path of base, collapsing duplicate and
+// trailing slashes while keeping the scheme, host and query intact.
+func joinURLPath(base string, elems ...string) string {
+	u, err := url.Parse(base)
+	if err != nil {
+		joined := strings.Trim(path.Join(elems...), "/")
+		if joined == "" {
+			return strings.TrimRight(base, "/")
+		}
+		return strings.TrimRight(base, "/") + "/" + joined
+	}
+	u.Path = strings.TrimRight(path.Join(append([]string{"/", u.Path}, elems...)...), "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+func (cfg *clientConfig) buildURL(baseURL string, jobKey string, action string, seq *int) string {
+	if cfg.urlBuilder != nil {
+		return cfg.urlBuilder(baseURL, jobKey, action, seq)
+	}
+	return DefaultURLBuilder(baseURL, jobKey, action, seq)
+}
+
+// seqPlaceholder stands in for the progress sequence number while building
+// templates; it is unlikely to appear anywhere else in a URL.
+const seqPlaceholder = 987654321
+
+// EndpointMap returns the URL template of each action on the primary
+// endpoint under the current options, with "{status}" and "{seq}" marking
+// the end status and progress sequence. It is meant for snapshot tests that
+// catch accidental routing changes.
+func (c *PingClient) EndpointMap() map[string]string {
+	cfg := c.config()
+	base := cfg.endpoints[0]
+	seq := seqPlaceholder
+	end := cfg.buildURL(base, c.jobKey, "end/{status}", nil)
+	end = strings.NewReplacer("%7Bstatus%7D", "{status}", "%7bstatus%7d", "{status}").Replace(end)
+	return map[string]string{
+		"ping":     cfg.buildURL(base, c.jobKey, "ping", nil),
+		"start":    cfg.buildURL(base, c.jobKey, "start", nil),
+		"skip":     cfg.buildURL(base, c.jobKey, "skip", nil),
+		"end":      end,
+		"progress": strings.Replace(cfg.buildURL(base, c.jobKey, "progress", &seq), strconv.Itoa(seq), "{seq}", 1),
+	}
+}
+
+func (c *PingClient) request(action string, r route, body map[string]any) (*PingSuccess, error) {
+	return c.requestContext(context.Background(), action, r, body)
+}
+
+func normalizeEndStatus(status string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(status))
+	if value == "cancelled" {
+		value = "canceled"
+	}
+	if value != "success" && value != "fail" && value != "canceled" {
+		return "", &ValidationError{Message: `Status must be "success", "fail", or "canceled".`}
+	}
+	return value, nil
+}
+
+func (c *PingClient) requestContext(ctx context.Context, action string, r route, body map[string]any) (*PingSuccess, error) {
+	return c.dispatch(ctx, action, r, body, c.send)
+}
+
+// sendFunc delivers one request once dispatch has let it through.
+type sendFunc func(ctx context.Context, cfg *clientConfig, action string, r route, body map[string]any) (*PingSuccess, error)
+
+// dispatch runs a request through the client's gates (quiet hours, initial
+// delay, rate limit, pacing and throttle), delivers it with send and records
+// the outcome in events, the audit log, counters, the offline queue and the
+// client state.
+func (c *PingClient) dispatch(ctx context.Context, action string, r route, body map[string]any, send sendFunc) (*PingSuccess, error) {
+	cfg := c.config().forAction(action)
+	if err := validateTraceID(c.call.TraceID); err != nil {
+		return nil, err
+	}
+	if cfg.quiet(action, c.now()) {
+		return &PingSuccess{Ok: false, Action: "skipped", JobKey: c.jobKey}, nil
+	}
+	if c.call.NoRetry {
+		single := *cfg
+		single.maxRetries = 0
+		cfg = &single
+	}
+	if err := c.waitInitialDelay(ctx, cfg); err != nil {
+		return nil, err
+	}
+	if cfg.limiter != nil {
+		if cfg.throttleDrop {
+			if !cfg.limiter.take(c.now()) {
+				c.dropped.Add(1)
+				return &PingSuccess{Ok: false, Action: "throttled", JobKey: c.jobKey}, nil
+			}
+		} else if wait := cfg.limiter.reserve(c.now()); wait > 0 {
+			c.sleep(wait)
+		}
+	}
+
+	if cfg.pacer != nil {
+		if wait := cfg.pacer.wait(c.now()); wait > 0 {
+			c.sleep(wait)
+		}
+	}
+	if cfg.throttle != nil {
+		if wait := cfg.throttle.wait(c.now()); wait > 0 {
+			c.sleep(wait)
+		}
+	}
+
+	body = c.decorateBody(cfg, body)
+	c.emit(cfg, StreamEvent{Type: EventRequest, Action: action})
+	sentAt := c.now()
+	res, err := send(withRequestInfo(ctx, c.jobKey, action), cfg, action, r, body)
+	if cfg.throttle != nil {
+		cfg.throttle.observe(err, c.now())
 	}
-	return c.request("progress", fmt.Sprintf("/ping/%s/progress", c.jobKey), body)
+	if err != nil {
+		c.emit(cfg, errorEvent(StreamEvent{Type: EventFailure, Action: action}, err))
+	} else {
+		c.emit(cfg, StreamEvent{Type: EventSuccess, Action: action})
+	}
+	c.audit(cfg, action, err)
+	c.counters.outcome(err)
+	c.enqueue(cfg, action, r, body, err)
+	c.stateMu.Lock()
+	c.lastResult, c.lastErr = res, err
+	if err == nil {
+		c.lastSuccess = res
+		switch action {
+		case "start":
+			c.runStartedAt = sentAt
+		case "end":
+			c.runStartedAt = time.Time{}
+		}
+	}
+	c.stateMu.Unlock()
+	if err == nil && res.Ok && cfg.onFirstSuccess != nil && c.firstOK.CompareAndSwap(false, true) {
+		cfg.onFirstSuccess(res)
+	}
+	if err == nil && cfg.onOverdue != nil && c.IsOverdue(res) {
+		cfg.onOverdue(res)
+	}
+	if err != nil && cfg.bestEffort {
+		return &PingSuccess{Ok: false, Action: action, JobKey: c.jobKey, TraceID: c.call.TraceID}, err
+	}
+	return res, err
 }
 
-func (c *PingClient) request(action string, path string, body map[string]any) (*PingSuccess, error) {
-	url := fmt.Sprintf("%s%s", c.baseURL, path)
+// decorateBody returns body with the host, resource and client_seq fields the
+// options ask for and the parent fields of a SubClient, leaving any value the
+// caller already set.
+func (c *PingClient) decorateBody(cfg *clientConfig, body map[string]any) map[string]any {
+	var resources map[string]any
+	if cfg.resourceMetrics {
+		resources = c.resources.sample(c.now())
+	}
+	parent := c.parentFields()
+	var seq map[string]any
+	if cfg.clientSeq {
+		seq = map[string]any{"client_seq": c.clientSeq.Add(1)}
+	}
+	if len(cfg.hostInfo) == 0 && len(resources) == 0 && len(parent) == 0 && len(seq) == 0 {
+		return body
+	}
+	out := make(map[string]any, len(body)+len(cfg.hostInfo)+len(resources)+len(parent)+len(seq))
+	for _, fields := range []map[string]any{cfg.hostInfo, resources, parent, seq, body} {
+		for key, value := range fields {
+			out[key] = value
+		}
+	}
+	return out
+}
+
+func (c *PingClient) send(ctx context.Context, cfg *clientConfig, action string, r route, body map[string]any) (*PingSuccess, error) {
+	first := c.pickEndpoint(cfg)
 
 	var payload []byte
 	var err error
 	if len(body) > 0 {
-		payload, err = json.Marshal(body)
+		var doc any = body
+		if cfg.envelopeKey != "" {
+			doc = map[string]any{cfg.envelopeKey: body}
+		}
+		payload, err = json.Marshal(doc)
 		if err != nil {
 			return nil, &SdkError{Message: "failed to encode request payload", Cause: err}
 		}
 	}
 
 	attempt := 0
+	var lastErr error
 	for {
-		res, reqErr := c.httpClient.Request(
-			"POST",
-			url,
-			map[string]string{
-				"Content-Type": "application/json",
-				"Accept":       "application/json",
-				"User-Agent":   c.userAgent,
+		if until, active := maintenanceUntil(); active {
+			apiErr := &ApiError{Code: CodeMaintenance, Message: "server is in maintenance until " + until.UTC().Format(time.RFC3339)}
+			c.log(cfg, LogEvent{Type: LogEventError, JobKey: c.jobKey, Action: action, Attempt: attempt + 1, Err: apiErr, Fields: c.call.LogFields})
+			return nil, apiErr
+		}
+		out := &OutgoingRequest{
+			Method: "POST",
+			URL:    cfg.buildURL(cfg.endpoints[(first+attempt)%len(cfg.endpoints)], c.jobKey, r.action, r.seq),
+			Headers: map[string]string{
+				"Accept":     cfg.accept,
+				"User-Agent": cfg.userAgentFor(action),
 			},
-			payload,
-			c.timeoutMs,
-		)
+			Body: payload,
+		}
+		if len(payload) > 0 {
+			out.Headers["Content-Type"] = cfg.contentType
+		}
+		if cfg.acceptLanguage != "" {
+			out.Headers["Accept-Language"] = cfg.acceptLanguage
+		}
+		if c.call.TraceID != "" {
+			out.Headers[cfg.traceIDHeader] = c.call.TraceID
+		}
+		if cfg.interceptor != nil {
+			if err := cfg.interceptor(out, attempt, lastErr); err != nil {
+				return nil, &SdkError{Message: "request interceptor failed", Cause: err}
+			}
+		}
 
+		if cfg.logger != nil {
+			c.log(cfg, LogEvent{Type: LogEventRequest, JobKey: c.jobKey, Action: action, Attempt: attempt + 1, Body: cfg.redact(out.Body), Fields: c.call.LogFields})
+		}
+		if cfg.slots != nil {
+			select {
+			case cfg.slots <- struct{}{}:
+			case <-ctx.Done():
+				return nil, &SdkError{Message: "waiting for a request slot", Cause: ctx.Err()}
+			}
+		}
+		c.counters.attempt(attempt > 0)
+		c.inFlight.Add(1)
+		started := c.now()
+		timeoutMs := cfg.timeoutMs
+		if attempt == 0 && cfg.firstTimeoutMs > 0 {
+			timeoutMs = cfg.firstTimeoutMs
+		}
+		var res *HttpResponse
+		var reqErr error
+		if cfg.dryRun {
+			res = c.recordDryRun(out)
+		} else {
+			res, reqErr = sendRequest(ctx, cfg.httpClient, out.Method, out.URL, out.Headers, out.Body, timeoutMs)
+		}
+		latency := c.now().Sub(started)
+		if reqErr == nil && cfg.pacer != nil {
+			cfg.pacer.observe(latency, c.now())
+		}
+		c.inFlight.Add(-1)
+		if cfg.slots != nil {
+			<-cfg.slots
+		}
+		if cfg.onExchange != nil {
+			cfg.onExchange(ExchangeInfo{
+				Action:  action,
+				Attempt: attempt + 1,
+				Method:  out.Method,
+				URL:     out.URL,
+				Headers: mergeMaps(out.Headers, nil),
+				Body:    append([]byte(nil), out.Body...),
+				Latency: latency,
+			}, res, reqErr)
+		}
+
+		var apiErr *ApiError
+		factor := 1.0
+		var retryAfter time.Duration
 		if reqErr != nil {
-			if attempt >= c.maxRetries {
-				return nil, &ApiError{
-					Code:      CodeNetwork,
-					Retryable: true,
-					Message:   reqErr.Error(),
-					Raw:       reqErr,
+			apiErr = &ApiError{
+				Code:      CodeNetwork,
+				Retryable: cfg.retryNetwork(reqErr),
+				Message:   reqErr.Error(),
+				Raw:       reqErr,
+			}
+		} else {
+			if cfg.onRawResponse != nil {
+				cfg.onRawResponse(action, res.Status, []byte(res.Body))
+			}
+
+			parsed := cfg.decodeBody(res.Body)
+			ok, code, retryable := cfg.classify(res, parsed)
+			if ok && cfg.isSuccessBody != nil && !cfg.isSuccessBody(parsed) {
+				ok, code, retryable = false, CodeServer, codeRetryable(CodeServer)
+			}
+			if cfg.retryableBody(parsed) {
+				if ok {
+					ok, code = false, CodeServer
 				}
+				retryable = true
+			}
+			if ok {
+				cfg.recordAttempt(AttemptMetric{Action: action, Status: res.Status, Attempt: attempt + 1, Latency: latency, Labels: c.call.Labels})
+				c.emit(cfg, StreamEvent{Type: EventAttempt, Action: action, Attempt: attempt + 1, Status: res.Status, LatencyMs: latencyMs(latency)})
+				if cfg.logger != nil {
+					c.log(cfg, LogEvent{Type: LogEventSuccess, JobKey: c.jobKey, Action: action, Status: res.Status, Attempt: attempt + 1, Latency: latency, Body: cfg.redact([]byte(res.Body)), Fields: c.call.LogFields})
+				}
+				if cfg.onSuccessRaw != nil {
+					cfg.onSuccessRaw(action, parsed, isReplay(ctx))
+				}
+				success := c.normalizeSuccess(action, parsed)
+				success.TraceID = c.call.TraceID
+				return success, nil
+			}
+
+			if isMaintenance(res, parsed) {
+				code, retryable = CodeMaintenance, c.handleMaintenance(cfg)
 			}
-			attempt++
-			c.sleepWithBackoff(attempt)
-			continue
-		}
 
-		parsed := safeJSON(res.Body)
-		if res.Status >= 200 && res.Status < 300 {
-			return c.normalizeSuccess(action, parsed), nil
+			msg := cfg.errorMessageOf(parsed)
+			if code == CodeRedirect {
+				msg = "unexpected redirect to " + headerValue(res.Headers, "Location")
+			}
+			if msg == "" {
+				msg = "Request failed"
+			}
+
+			status := res.Status
+			apiErr = &ApiError{
+				Code:        code,
+				HTTPStatus:  &status,
+				Retryable:   retryable,
+				Message:     msg,
+				Raw:         parsed,
+				BodyPreview: bodyPreview(string(cfg.redact([]byte(res.Body))), cfg.previewBytes),
+			}
+			factor = backpressureFactor(res.Headers)
+			retryAfter = c.retryAfter(res)
 		}
 
-		code, retryable := mapError(res.Status)
-		msg, _ := parsed["message"].(string)
-		if msg == "" {
-			msg = "Request failed"
+		event := LogEvent{JobKey: c.jobKey, Action: action, Attempt: attempt + 1, Latency: latency, Err: apiErr, Fields: c.call.LogFields}
+		if apiErr.HTTPStatus != nil {
+			event.Status = *apiErr.HTTPStatus
+		}
+		if res != nil && cfg.logger != nil {
+			event.Body = cfg.redact([]byte(res.Body))
 		}
+		cfg.recordAttempt(AttemptMetric{Action: action, Status: event.Status, Code: apiErr.Code, Attempt: attempt + 1, Latency: latency, Labels: c.call.Labels})
+		c.emit(cfg, errorEvent(StreamEvent{Type: EventAttempt, Action: action, Attempt: attempt + 1, LatencyMs: latencyMs(latency)}, apiErr))
 
-		if retryable && attempt < c.maxRetries {
-			attempt++
-			c.sleepWithBackoff(attempt)
-			continue
+		if ctx.Err() != nil || !c.retryAllowed(cfg, apiErr, attempt) {
+			event.Type = LogEventError
+			c.log(cfg, event)
+			return nil, apiErr
 		}
+		event.Type = LogEventRetry
+		c.log(cfg, event)
+		c.emit(cfg, errorEvent(StreamEvent{Type: EventRetry, Action: action, Attempt: attempt + 2}, apiErr))
+		lastErr = apiErr
+		attempt++
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt, retryReason(apiErr.Code), apiErr)
+		}
+		if c.sleepWithBackoff(ctx, cfg, attempt, factor, retryAfter, apiErr) != nil {
+			return nil, apiErr
+		}
+	}
+}
+
+// retryAllowed reports whether another attempt may follow attempt (0-based)
+// after it failed with apiErr, spending from the retry budget if so.
+func (c *PingClient) retryAllowed(cfg *clientConfig, apiErr *ApiError, attempt int) bool {
+	if !apiErr.Retryable || attempt >= cfg.maxRetries {
+		return false
+	}
+	return cfg.retryBudget == nil || cfg.retryBudget.take(c.now())
+}
+
+func (cfg *clientConfig) retryNetwork(err error) bool {
+	var dnsErr *net.DNSError
+	if cfg.failFastDNS && errors.As(err, &dnsErr) {
+		return false
+	}
+	if cfg.networkRetryable == nil || cfg.networkRetryable(err) {
+		return true
+	}
+	return !cfg.noResetRetry && isConnReset(err)
+}
 
-		status := res.Status
-		return nil, &ApiError{
-			Code:       code,
-			HTTPStatus: &status,
-			Retryable:  retryable,
-			Message:    msg,
-			Raw:        parsed,
+// isConnReset reports whether err is the connection being dropped mid-request,
+// as load balancers do when recycling idle connections.
+func isConnReset(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (cfg *clientConfig) classify(res *HttpResponse, parsed map[string]any) (bool, ApiErrorCode, bool) {
+	if cfg.successDetect != nil {
+		ok, code := cfg.successDetect(res, parsed)
+		if ok {
+			return true, "", false
+		}
+		if code == "" {
+			code = CodeUnknown
+		}
+		return false, code, codeRetryable(code)
+	}
+	if cfg.successStatuses != nil {
+		if cfg.successStatuses[res.Status] {
+			return true, "", false
 		}
+	} else if res.Status >= 200 && res.Status < 300 {
+		return true, "", false
 	}
+	code, retryable := MapError(res.Status)
+	return false, code, retryable
 }
 
 func (c *PingClient) normalizeSuccess(action string, payload map[string]any) *PingSuccess {
@@ -263,17 +1154,190 @@ func (c *PingClient) normalizeSuccess(action string, payload map[string]any) *Pi
 	}
 }
 
-func (c *PingClient) sleepWithBackoff(attempt int) {
-	baseMs := float64(c.retryBackoffMs) * math.Pow(2, float64(maxInt(0, attempt-1)))
+// maxRetryAfter caps how long a server's Retry-After can hold up a retry.
+const maxRetryAfter = time.Minute
+
+// waitInitialDelay holds back requests until InitialDelayMs has passed since
+// the client's first request. Concurrent first calls all wait for the same
+// timer; each stops waiting early if its own ctx is canceled.
+func (c *clientCore) waitInitialDelay(ctx context.Context, cfg *clientConfig) error {
+	c.initialOnce.Do(func() {
+		c.initialDone = make(chan struct{})
+		if cfg.initialDelay <= 0 {
+			close(c.initialDone)
+			return
+		}
+		timer := c.after(cfg.initialDelay)
+		go func() {
+			<-timer
+			close(c.initialDone)
+		}()
+	})
+	select {
+	case <-c.initialDone:
+		return nil
+	default:
+	}
+	select {
+	case <-ctx.Done():
+		return &SdkError{Message: "request canceled during InitialDelayMs", Cause: ctx.Err()}
+	case <-c.initialDone:
+		return nil
+	}
+}
+
+// sleepWithBackoff waits before retry number attempt. The delay is the
+// exponential backoff with jitter and backpressure factor, raised to the
+// server's Retry-After if that is longer, then passed to BackoffOverride.
+func (c *PingClient) sleepWithBackoff(ctx context.Context, cfg *clientConfig, attempt int, factor float64, retryAfter time.Duration, lastErr error) error {
+	backoff := cfg.backoffMs(attempt, factor)
 	jitter := 0
-	if c.retryJitterMs > 0 {
-		jitter = c.rng.Intn(c.retryJitterMs + 1)
+	if maxJitter := cfg.maxJitterMs(backoff); maxJitter > 0 {
+		jitter = c.randIntn(maxJitter + 1)
+	}
+	delay := time.Duration(maxInt(backoff+jitter, cfg.retryMinMs)) * time.Millisecond
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	if cfg.backoffOverride != nil {
+		delay = cfg.backoffOverride(attempt, delay, lastErr)
+	}
+	if delay < 0 {
+		delay = 0
+	}
+	return c.sleepContext(ctx, delay)
+}
+
+// retryAfter reads the Retry-After header of a 429 or 503 response, given in
+// seconds or as an HTTP date, capped at maxRetryAfter.
+func (c *PingClient) retryAfter(res *HttpResponse) time.Duration {
+	if res.Status != 429 && res.Status != 503 {
+		return 0
+	}
+	raw := strings.TrimSpace(headerValue(res.Headers, "Retry-After"))
+	if raw == "" {
+		return 0
+	}
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(raw); err == nil {
+		wait = at.Sub(c.now())
+	}
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return wait
+}
+
+func (cfg *clientConfig) backoffMs(attempt int, factor float64) int {
+	return int(float64(cfg.retryBackoffMs) * math.Pow(cfg.retryMultiplier, float64(maxInt(0, attempt-1))) * factor)
+}
+
+func (cfg *clientConfig) errorMessageOf(parsed map[string]any) string {
+	if cfg.errorMessage != nil {
+		return cfg.errorMessage(parsed)
+	}
+	msg, _ := parsed["message"].(string)
+	return msg
+}
+
+// maxJitterMs is the upper bound of the random delay added to backoffMs:
+// RetryJitterPercent of it when set, RetryJitterMs otherwise.
+func (cfg *clientConfig) maxJitterMs(backoffMs int) int {
+	if cfg.jitterPercent > 0 {
+		return int(float64(backoffMs) * cfg.jitterPercent / 100)
+	}
+	return cfg.retryJitterMs
+}
+
+// BackoffSchedule returns the delay before each retry of a request under the
+// current options, without jitter or server backpressure.
+func (c *PingClient) BackoffSchedule() []time.Duration {
+	return c.backoffSchedule(false)
+}
+
+// BackoffScheduleMax is like BackoffSchedule but with the largest jitter
+// added to each delay, giving the longest a retry can wait.
+func (c *PingClient) BackoffScheduleMax() []time.Duration {
+	return c.backoffSchedule(true)
+}
+
+func (c *PingClient) backoffSchedule(withJitter bool) []time.Duration {
+	cfg := c.config()
+	schedule := make([]time.Duration, cfg.maxRetries)
+	for i := range schedule {
+		backoff := cfg.backoffMs(i+1, 1)
+		if withJitter {
+			backoff += cfg.maxJitterMs(backoff)
+		}
+		waitMs := maxInt(backoff, cfg.retryMinMs)
+		schedule[i] = time.Duration(waitMs) * time.Millisecond
+	}
+	return schedule
+}
+
+// backpressureFactor reads the server's X-Cronbeats-Backpressure load factor.
+// Missing, unparseable or non-positive values yield 1 so the backoff is unchanged.
+func backpressureFactor(headers map[string]string) float64 {
+	raw := strings.TrimSpace(headerValue(headers, "X-Cronbeats-Backpressure"))
+	if raw == "" {
+		return 1
+	}
+	factor, err := strconv.ParseFloat(raw, 64)
+	if err != nil || factor <= 0 || math.IsInf(factor, 0) || math.IsNaN(factor) {
+		return 1
+	}
+	return factor
+}
+
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[strings.ToLower(name)]; ok {
+		return v
+	}
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+	return ""
+}
+
+// pickEndpoint returns the index of the endpoint a request starts with.
+// Retries then walk the endpoint list from there.
+func (c *PingClient) pickEndpoint(cfg *clientConfig) int {
+	if len(cfg.weights) == 0 {
+		return 0
+	}
+	total := 0
+	for _, w := range cfg.weights {
+		total += w
+	}
+	n := c.randIntn(total)
+	for i, w := range cfg.weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return 0
+}
+
+func (c *PingClient) randIntn(n int) int {
+	if c.call.Rand != nil {
+		return c.call.Rand.Intn(n)
 	}
-	waitMs := int(baseMs) + jitter
-	c.sleep(time.Duration(waitMs) * time.Millisecond)
+	c.rngMu.Lock()
+	defer c.rngMu.Unlock()
+	return c.rng.Intn(n)
 }
 
-func mapError(status int) (ApiErrorCode, bool) {
+// MapError returns the error code the client assigns to a non-2xx HTTP
+// status and whether a request failing with it is retried.
+func MapError(status int) (ApiErrorCode, bool) {
 	if status == 400 {
 		return CodeValidation, false
 	}
@@ -283,15 +1347,26 @@ func mapError(status int) (ApiErrorCode, bool) {
 	if status == 429 {
 		return CodeRateLimit, true
 	}
+	if status >= 300 && status < 400 {
+		return CodeRedirect, false
+	}
 	if status >= 500 {
 		return CodeServer, true
 	}
 	return CodeUnknown, false
 }
 
-func safeJSON(raw string) map[string]any {
+func codeRetryable(code ApiErrorCode) bool {
+	return code == CodeRateLimit || code == CodeServer || code == CodeNetwork
+}
+
+func safeJSON(raw string, useNumber bool) map[string]any {
+	dec := json.NewDecoder(strings.NewReader(raw))
+	if useNumber {
+		dec.UseNumber()
+	}
 	var decoded any
-	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+	if err := dec.Decode(&decoded); err != nil || dec.More() {
 		return map[string]any{"message": "Invalid JSON response"}
 	}
 	obj, ok := decoded.(map[string]any)
@@ -301,6 +1376,50 @@ func safeJSON(raw string) map[string]any {
 	return obj
 }
 
+func bodyPreview(body string, limit int) string {
+	if len(body) > limit {
+		return body[:limit]
+	}
+	return body
+}
+
+// truncateMessage cuts msg to at most maxRunes runes and, when maxBytes is
+// set, to at most maxBytes bytes, never splitting a UTF-8 sequence.
+func truncateMessage(msg string, maxRunes int, maxBytes int) string {
+	runes := 0
+	for i := range msg {
+		if runes == maxRunes || (maxBytes > 0 && i > maxBytes) {
+			return trimToBytes(msg[:i], maxBytes)
+		}
+		runes++
+	}
+	return trimToBytes(msg, maxBytes)
+}
+
+func trimToBytes(msg string, maxBytes int) string {
+	if maxBytes <= 0 || len(msg) <= maxBytes {
+		return msg
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(msg[cut]) {
+		cut--
+	}
+	return msg[:cut]
+}
+
+func parseTimestamp(raw string) (time.Time, error) {
+	value := strings.TrimSpace(raw)
+	var lastErr error
+	for _, layout := range timestampLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+		lastErr = err
+	}
+	return time.Time{}, lastErr
+}
+
 func floatOrZero(v any) float64 {
 	switch x := v.(type) {
 	case float64:
@@ -313,6 +1432,10 @@ func floatOrZero(v any) float64 {
 		return float64(x)
 	case int32:
 		return float64(x)
+	case uint64:
+		return float64(x)
+	case uint32:
+		return float64(x)
 	case json.Number:
 		f, err := x.Float64()
 		if err == nil {
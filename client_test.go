@@ -1,15 +1,30 @@
 package cronbeatsgo
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
 	"time"
 )
 
 type stubResponse struct {
-	status int
-	body   string
+	status  int
+	body    string
+	headers map[string]string
 }
 
 type stubCall struct {
@@ -42,35 +57,25 @@ func (s *stubHTTPClient) Request(method string, url string, _ map[string]string,
 
 	next := s.responses[0]
 	s.responses = s.responses[1:]
-	return &HttpResponse{Status: next.status, Body: next.body, Headers: map[string]string{}}, nil
+	headers := next.headers
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	return &HttpResponse{Status: next.status, Body: next.body, Headers: headers}, nil
 }
 
 func newTestClient(t *testing.T, httpClient HttpClient, opts *Options) *PingClient {
 	t.Helper()
 	base := &Options{
-		HTTPClient:     httpClient,
-		MaxRetries:     0,
 		RetryBackoffMs: 1,
-		RetryJitterMs:  0,
 	}
 	if opts != nil {
-		if opts.BaseURL != "" {
-			base.BaseURL = opts.BaseURL
-		}
-		if opts.TimeoutMs != 0 {
-			base.TimeoutMs = opts.TimeoutMs
-		}
-		if opts.MaxRetries != 0 {
-			base.MaxRetries = opts.MaxRetries
-		}
-		if opts.RetryBackoffMs != 0 {
-			base.RetryBackoffMs = opts.RetryBackoffMs
-		}
-		base.RetryJitterMs = opts.RetryJitterMs
-		if opts.UserAgent != "" {
-			base.UserAgent = opts.UserAgent
+		*base = *opts
+		if base.RetryBackoffMs == 0 {
+			base.RetryBackoffMs = 1
 		}
 	}
+	base.HTTPClient = httpClient
 
 	client, err := NewPingClient("abc123de", base)
 	if err != nil {
@@ -237,3 +242,1624 @@ func TestProgressTruncationAndSeqPath(t *testing.T) {
 		t.Fatalf("expected truncated message length 255, got %d", len(msg))
 	}
 }
+
+func TestEndTimerSendsDuration(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	timer := client.StartTimer()
+	current = current.Add(1500 * time.Millisecond)
+
+	if _, err := client.EndTimer(timer, "fail"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].url; got != "https://cronbeats.io/ping/abc123de/end/fail" {
+		t.Fatalf("unexpected url: %s", got)
+	}
+
+	var sent map[string]any
+	if err := json.Unmarshal([]byte(http.calls[0].body), &sent); err != nil {
+		t.Fatalf("failed to decode request body: %v", err)
+	}
+	if sent["duration_ms"] != 1500.0 {
+		t.Fatalf("expected duration_ms 1500, got %v", sent["duration_ms"])
+	}
+
+	if _, err := client.EndTimer(Timer{}, "success"); err == nil {
+		t.Fatal("expected validation error for zero timer")
+	}
+}
+
+func TestSuccessStatuses(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{
+		{status: 202, body: `{}`},
+		{status: 200, body: `{}`},
+		{status: 299, body: `{}`},
+		{status: 302, body: `{}`},
+	}}
+	client := newTestClient(t, http, &Options{SuccessStatuses: []int{202, 302}})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("expected 202 to succeed, got %v", err)
+	}
+	var apiErr *ApiError
+	for _, status := range []int{200, 299} {
+		if _, err := client.Ping(); !errors.As(err, &apiErr) || *apiErr.HTTPStatus != status {
+			t.Fatalf("expected %d to fail when not listed, got %v", status, err)
+		}
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("expected custom status 302 to succeed, got %v", err)
+	}
+
+	var vErr *ValidationError
+	if _, err := NewPingClient("abc123de", &Options{SuccessStatuses: []int{42}}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestSuccessDetectorOverridesStatusCheck(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"upstream_status":503,"message":"Upstream unavailable"}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{
+		SuccessDetector: func(resp *HttpResponse, parsed map[string]any) (bool, ApiErrorCode) {
+			if status, _ := parsed["upstream_status"].(float64); status >= 500 {
+				return false, CodeServer
+			}
+			return resp.Status == 200, CodeUnknown
+		},
+	})
+	client.cfg.maxRetries = 0
+
+	_, err := client.Ping()
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected ApiError, got %T", err)
+	}
+	if apiErr.Code != CodeServer || !apiErr.Retryable || apiErr.Message != "Upstream unavailable" {
+		t.Fatalf("unexpected api error: %#v", apiErr)
+	}
+}
+
+func TestOnRawResponseSeesEveryBody(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 502, body: `<html>Bad Gateway</html>`},
+			{status: 200, body: `{"action":"ping"}`},
+		},
+	}
+	var seen []string
+	client := newTestClient(t, http, &Options{
+		MaxRetries: 1,
+		OnRawResponse: func(action string, status int, body []byte) {
+			seen = append(seen, fmt.Sprintf("%s %d %s", action, status, body))
+		},
+	})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"ping 502 <html>Bad Gateway</html>", `ping 200 {"action":"ping"}`}
+	if len(seen) != len(want) || seen[0] != want[0] || seen[1] != want[1] {
+		t.Fatalf("unexpected raw responses: %q", seen)
+	}
+}
+
+func TestBackpressureHeaderScalesBackoff(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 503, body: `{}`, headers: map[string]string{"x-cronbeats-backpressure": "2.5"}},
+			{status: 503, body: `{}`, headers: map[string]string{"X-Cronbeats-Backpressure": "bogus"}},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 2, RetryBackoffMs: 100})
+	client.cfg.retryJitterMs = 0
+
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delays) != 2 || delays[0] != 250*time.Millisecond || delays[1] != 200*time.Millisecond {
+		t.Fatalf("unexpected backoff delays: %v", delays)
+	}
+}
+
+func TestPingAtSendsTimestamp(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+	client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := client.PingAt("2026-02-25 11:30:00"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].body; got != `{"timestamp":"2026-02-25T11:30:00Z"}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+
+	for _, input := range []string{"yesterday", "2026-02-25T13:00:00Z"} {
+		var vErr *ValidationError
+		if _, err := client.PingAt(input); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %q, got %v", input, err)
+		}
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected rejected timestamps not to be sent, got %d calls", len(http.calls))
+	}
+}
+
+func TestRetryMinBackoffFloorsEveryDelay(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBackoffMs: 10, RetryMinBackoffMs: 30})
+	client.cfg.retryJitterMs = 0
+
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []time.Duration{30 * time.Millisecond, 30 * time.Millisecond, 40 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("expected %d delays, got %v", len(want), delays)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Fatalf("unexpected delays: %v", delays)
+		}
+	}
+}
+
+func TestRetryAfterAndBackoffOverride(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{
+		{status: 429, body: `{}`, headers: map[string]string{"retry-after": "3"}},
+		{status: 503, body: `{}`, headers: map[string]string{"retry-after": "Wed, 25 Feb 2026 12:00:02 GMT"}},
+		{status: 500, body: `{}`, headers: map[string]string{"retry-after": "600"}},
+		{status: 200, body: `{}`},
+	}}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBackoffMs: 100})
+	client.cfg.retryJitterMs = 0
+	client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []time.Duration{3 * time.Second, 2 * time.Second, 400 * time.Millisecond}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Fatalf("expected Retry-After on 429 and 503 only, got %v", delays)
+	}
+
+	http.responses = []stubResponse{
+		{status: 429, body: `{}`, headers: map[string]string{"retry-after": "3"}},
+		{status: 500, body: `{}`},
+		{status: 200, body: `{}`},
+	}
+	var seen []time.Duration
+	client.cfg.backoffOverride = func(attempt int, defaultDelay time.Duration, lastErr error) time.Duration {
+		seen = append(seen, defaultDelay)
+		var apiErr *ApiError
+		if errors.As(lastErr, &apiErr) && apiErr.Code == CodeServer {
+			return 10 * defaultDelay
+		}
+		return time.Second
+	}
+	delays = nil
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(seen) != fmt.Sprint([]time.Duration{3 * time.Second, 200 * time.Millisecond}) {
+		t.Fatalf("unexpected default delays: %v", seen)
+	}
+	if fmt.Sprint(delays) != fmt.Sprint([]time.Duration{time.Second, 2 * time.Second}) {
+		t.Fatalf("expected overridden delays, got %v", delays)
+	}
+}
+
+func TestRetryJitterPercent(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBackoffMs: 100, RetryJitterMs: 5000, RetryJitterPercent: 50})
+
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	base := client.BackoffSchedule()
+	max := client.BackoffScheduleMax()
+	want := []time.Duration{150 * time.Millisecond, 300 * time.Millisecond, 600 * time.Millisecond}
+	if fmt.Sprint(max) != fmt.Sprint(want) {
+		t.Fatalf("unexpected max schedule: %v", max)
+	}
+	for i, d := range delays {
+		if d < base[i] || d > max[i] {
+			t.Fatalf("delay %v outside [%v, %v]", d, base[i], max[i])
+		}
+	}
+
+	for _, bad := range []float64{-1, 101, math.NaN()} {
+		var vErr *ValidationError
+		if _, err := NewPingClient("abc123de", &Options{RetryJitterPercent: bad}); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for percent %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestRetryBackoffMultiplier(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 500, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBackoffMs: 100, RetryBackoffMultiplier: 1.5})
+	client.cfg.retryJitterMs = 0
+
+	var delays []time.Duration
+	client.sleep = func(d time.Duration) { delays = append(delays, d) }
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []time.Duration{100 * time.Millisecond, 150 * time.Millisecond, 225 * time.Millisecond}
+	if fmt.Sprint(delays) != fmt.Sprint(want) {
+		t.Fatalf("unexpected delays: %v", delays)
+	}
+	if schedule := client.BackoffSchedule(); fmt.Sprint(schedule) != fmt.Sprint(want) {
+		t.Fatalf("unexpected schedule: %v", schedule)
+	}
+
+	for _, bad := range []float64{1, 0.5, -2, math.Inf(1), math.NaN()} {
+		var vErr *ValidationError
+		if _, err := NewPingClient("abc123de", &Options{RetryBackoffMultiplier: bad}); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for multiplier %v, got %v", bad, err)
+		}
+	}
+}
+
+func TestOnRetryReportsReason(t *testing.T) {
+	http := &stubHTTPClient{
+		networkFailures: 1,
+		responses: []stubResponse{
+			{status: 429, body: `{}`},
+			{status: 503, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	var reasons []RetryReason
+	client := newTestClient(t, http, &Options{
+		MaxRetries: 3,
+		OnRetry: func(attempt int, reason RetryReason, err error) {
+			if attempt != len(reasons)+1 || err == nil {
+				t.Errorf("unexpected retry callback: attempt=%d err=%v", attempt, err)
+			}
+			reasons = append(reasons, reason)
+		},
+	})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []RetryReason{ReasonNetwork, ReasonRateLimit, ReasonServer}
+	if len(reasons) != len(want) || reasons[0] != want[0] || reasons[1] != want[1] || reasons[2] != want[2] {
+		t.Fatalf("unexpected retry reasons: %v", reasons)
+	}
+}
+
+func TestNoContentIsSuccess(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 204, body: ""}}}
+	client := newTestClient(t, http, nil)
+
+	res, err := client.Start()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Ok || res.Action != "start" || res.JobKey != "abc123de" || len(res.Raw) != 0 {
+		t.Fatalf("unexpected result for 204: %+v", res)
+	}
+}
+
+func TestApiErrorIncludesBodyPreview(t *testing.T) {
+	page := "<html><body>" + strings.Repeat("x", 100) + "</body></html>"
+	http := &stubHTTPClient{responses: []stubResponse{{status: 404, body: page}}}
+	client := newTestClient(t, http, &Options{BodyPreviewBytes: 20})
+
+	_, err := client.Ping()
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected ApiError, got %T", err)
+	}
+	if apiErr.BodyPreview != page[:20] {
+		t.Fatalf("unexpected body preview: %q", apiErr.BodyPreview)
+	}
+	if apiErr.Raw.(map[string]any)["message"] != "Invalid JSON response" {
+		t.Fatalf("unexpected raw: %#v", apiErr.Raw)
+	}
+}
+
+func TestNewPingClientFromURL(t *testing.T) {
+	cases := map[string]string{
+		"https://cronbeats.io/ping/YCrXzYbV":         "https://cronbeats.io",
+		"https://eu.example.com/api/ping/YCrXzYbV/":  "https://eu.example.com/api",
+		"http://localhost:8080/ping/YCrXzYbV?src=ui": "http://localhost:8080",
+	}
+	for input, wantBase := range cases {
+		client, err := NewPingClientFromURL(input, &Options{BaseURL: "https://ignored.example"})
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", input, err)
+		}
+		if client.jobKey != "YCrXzYbV" || client.config().baseURL != wantBase {
+			t.Fatalf("unexpected client for %q: key=%s base=%s", input, client.jobKey, client.config().baseURL)
+		}
+	}
+
+	for _, input := range []string{"YCrXzYbV", "https://cronbeats.io/jobs/YCrXzYbV", "https://cronbeats.io/ping/short"} {
+		var vErr *ValidationError
+		if _, err := NewPingClientFromURL(input, nil); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %q, got %v", input, err)
+		}
+	}
+}
+
+func TestFallbackBaseURLsUsedOnRetry(t *testing.T) {
+	http := &stubHTTPClient{
+		networkFailures: 1,
+		responses:       []stubResponse{{status: 200, body: `{}`}},
+	}
+	client := newTestClient(t, http, &Options{
+		MaxRetries:       2,
+		FallbackBaseURLs: []string{"https://eu.cronbeats.io/"},
+	})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de" || http.calls[1].url != "https://eu.cronbeats.io/ping/abc123de" {
+		t.Fatalf("unexpected failover urls: %v", http.calls)
+	}
+}
+
+func TestEndpointWeightsPickInitialEndpoint(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{
+		FallbackBaseURLs: []string{"https://eu.cronbeats.io"},
+		EndpointWeights:  []int{3, 1},
+	})
+	client.rng = rand.New(rand.NewSource(1))
+
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		counts[http.calls[i].url]++
+	}
+	primary := counts["https://cronbeats.io/ping/abc123de"]
+	secondary := counts["https://eu.cronbeats.io/ping/abc123de"]
+	if primary+secondary != 400 || secondary < 60 || secondary > 140 {
+		t.Fatalf("unexpected weighted distribution: primary=%d secondary=%d", primary, secondary)
+	}
+
+	if _, err := NewPingClient("abc123de", &Options{EndpointWeights: []int{1, 1}}); err == nil {
+		t.Fatal("expected validation error for mismatched weights")
+	}
+}
+
+func TestProgressStructuredFields(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	current, total := 3, 10
+	_, err := client.Progress(ProgressOptions{
+		Current: &current,
+		Total:   &total,
+		Message: "3/10 complete",
+		Fields:  map[string]any{"stage": "import", "message": "ignored"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].body; got != `{"current":3,"message":"3/10 complete","stage":"import","total":10}` {
+		t.Fatalf("unexpected body: %s", got)
+	}
+
+	over := 11
+	var vErr *ValidationError
+	if _, err := client.Progress(ProgressOptions{Current: &over, Total: &total}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for current > total, got %v", err)
+	}
+}
+
+func TestProgressAcceptsFloat(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.Progress(42.6, "rounding"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].url; got != "https://cronbeats.io/ping/abc123de/progress/43" {
+		t.Fatalf("unexpected url: %s", got)
+	}
+}
+
+func TestRetryBudgetLimitsRetriesAcrossRequests(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 100}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryBudgetPerSecond: 2})
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	if len(http.calls) != 4 {
+		t.Fatalf("expected budget of 2 retries to allow 4 calls, got %d", len(http.calls))
+	}
+
+	current = current.Add(500 * time.Millisecond)
+	_, _ = client.Ping()
+	if len(http.calls) != 6 {
+		t.Fatalf("expected one refilled retry, got %d calls", len(http.calls))
+	}
+}
+
+func TestPingCheckedDetectsUnhealthyResponses(t *testing.T) {
+	cases := map[string]bool{
+		`{"status":"success","next_expected":"2026-02-25 12:05:00"}`: true,
+		`{"status":"success","next_expected":"2026-02-25 11:55:00"}`: false,
+		`{"status":"warning"}`:                false,
+		`{"status":"success","overdue":true}`: false,
+		`{"healthy":false}`:                   false,
+		`{}`:                                  true,
+	}
+	for body, healthy := range cases {
+		http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: body}}}
+		client := newTestClient(t, http, nil)
+		client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+
+		err := client.PingChecked()
+		var hErr *HealthError
+		if healthy && err != nil {
+			t.Fatalf("expected %s to be healthy, got %v", body, err)
+		}
+		if !healthy && !errors.As(err, &hErr) {
+			t.Fatalf("expected HealthError for %s, got %v", body, err)
+		}
+	}
+}
+
+func TestEndAcceptsCanceled(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.Cancel(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.End("Cancelled"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, call := range http.calls {
+		if call.url != "https://cronbeats.io/ping/abc123de/end/canceled" {
+			t.Fatalf("unexpected url: %s", call.url)
+		}
+	}
+
+	var vErr *ValidationError
+	if _, err := client.End("aborted"); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestTruncateMessageRespectsRunesAndBytes(t *testing.T) {
+	cases := []struct {
+		msg      string
+		maxRunes int
+		maxBytes int
+		want     string
+	}{
+		{msg: "héllo wörld", maxRunes: 5, want: "héllo"},
+		{msg: "héllo wörld", maxRunes: 255, maxBytes: 2, want: "h"},
+		{msg: "héllo wörld", maxRunes: 255, maxBytes: 3, want: "hé"},
+		{msg: "日本語テキスト", maxRunes: 4, maxBytes: 10, want: "日本語"},
+		{msg: "日本語", maxRunes: 255, maxBytes: 9, want: "日本語"},
+		{msg: "short", maxRunes: 255, want: "short"},
+	}
+	for _, tc := range cases {
+		got := truncateMessage(tc.msg, tc.maxRunes, tc.maxBytes)
+		if got != tc.want {
+			t.Fatalf("truncateMessage(%q, %d, %d) = %q, want %q", tc.msg, tc.maxRunes, tc.maxBytes, got, tc.want)
+		}
+	}
+}
+
+func TestBestEffortResultOnFailure(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 404, body: `{"message":"Job not found"}`}}}
+	client := newTestClient(t, http, &Options{BestEffortResult: true})
+
+	res, err := client.Start()
+	if err == nil {
+		t.Fatal("expected error to still be returned")
+	}
+	if res == nil || res.Ok || res.Action != "start" || res.JobKey != "abc123de" {
+		t.Fatalf("unexpected best-effort result: %#v", res)
+	}
+}
+
+func TestEndWithMessageTruncatesSummary(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 8})
+
+	if _, err := client.EndWithMessage("success", "processed 1200 records"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/end/success" || http.calls[0].body != `{"message":"processe"}` {
+		t.Fatalf("unexpected call: %#v", http.calls[0])
+	}
+}
+
+func TestNextPingUsesLastResponse(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"next_expected":"2026-02-25 12:05:00"}`},
+			{status: 500, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, nil)
+	client.cfg.maxRetries = 0
+
+	if _, err := client.NextPing(); !errors.Is(err, ErrNoResponse) {
+		t.Fatalf("expected ErrNoResponse, got %v", err)
+	}
+
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	next, err := client.NextPing()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !next.Equal(time.Date(2026, 2, 25, 12, 5, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next ping: %v", next)
+	}
+
+	_, _ = client.Ping()
+	if _, err := client.NextPing(); !errors.Is(err, ErrNoNextExpected) {
+		t.Fatalf("expected ErrNoNextExpected, got %v", err)
+	}
+}
+
+type resetClient struct {
+	failures []error
+	calls    int
+}
+
+func (r *resetClient) Request(string, string, map[string]string, []byte, int) (*HttpResponse, error) {
+	r.calls++
+	if len(r.failures) > 0 {
+		err := r.failures[0]
+		r.failures = r.failures[1:]
+		return nil, &SdkError{Message: "network request failed", Cause: err}
+	}
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestConnectionResetRetriedDespiteNetworkRetryable(t *testing.T) {
+	noNetworkRetries := func(error) bool { return false }
+	reset := &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+
+	http := &resetClient{failures: []error{reset, io.EOF}}
+	client := newTestClient(t, http, &Options{MaxRetries: 2, NetworkRetryable: noNetworkRetries})
+	if _, err := client.Ping(); err != nil || http.calls != 3 {
+		t.Fatalf("expected resets to be retried, got %v after %d calls", err, http.calls)
+	}
+
+	http = &resetClient{failures: []error{errors.New("dial tcp: no such host")}}
+	client = newTestClient(t, http, &Options{MaxRetries: 2, NetworkRetryable: noNetworkRetries})
+	if _, err := client.Ping(); err == nil || http.calls != 1 {
+		t.Fatalf("expected other network errors not to be retried, got %v after %d calls", err, http.calls)
+	}
+
+	http = &resetClient{failures: []error{reset}}
+	client = newTestClient(t, http, &Options{MaxRetries: 2, NetworkRetryable: noNetworkRetries, DisableConnResetRetry: true})
+	var apiErr *ApiError
+	if _, err := client.Ping(); !errors.As(err, &apiErr) || apiErr.Retryable || http.calls != 1 {
+		t.Fatalf("expected reset retry to be disabled, got %v after %d calls", err, http.calls)
+	}
+}
+
+func TestFailFastDNS(t *testing.T) {
+	dnsErr := &net.DNSError{Err: "no such host", Name: "cronbeats.invalid", IsNotFound: true}
+
+	http := &resetClient{failures: []error{dnsErr, dnsErr}}
+	client := newTestClient(t, http, &Options{MaxRetries: 2})
+	if _, err := client.Ping(); err != nil || http.calls != 3 {
+		t.Fatalf("expected DNS errors to be retried by default, got %v after %d calls", err, http.calls)
+	}
+
+	http = &resetClient{failures: []error{dnsErr}}
+	client = newTestClient(t, http, &Options{MaxRetries: 2, FailFastDNS: true})
+	var apiErr *ApiError
+	if _, err := client.Ping(); !errors.As(err, &apiErr) || apiErr.Code != CodeNetwork || apiErr.Retryable || http.calls != 1 {
+		t.Fatalf("expected an immediate network error, got %v after %d calls", err, http.calls)
+	}
+}
+
+func TestRetryableErrorRetriesBodySignals(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"error":"transient"}`},
+			{status: 400, body: `{"error":"transient"}`},
+			{status: 200, body: `{"action":"ping"}`},
+		},
+	}
+	opts := (&Options{MaxRetries: 2}).WithRetryableError(func(parsed map[string]any) bool {
+		return parsed["error"] == "transient"
+	})
+	client := newTestClient(t, http, opts)
+
+	res, err := client.Ping()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Ok || len(http.calls) != 3 {
+		t.Fatalf("expected success on third call, got %#v after %d calls", res, len(http.calls))
+	}
+}
+
+func TestRetryableBodyCodesRetryWhateverTheStatus(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"code":"TEMP_LOCK"}`},
+			{status: 409, body: `{"code":"TEMP_LOCK"}`},
+			{status: 503, body: `{"code":"OTHER"}`},
+			{status: 200, body: `{"code":"OK"}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 3, RetryableBodyCodes: []string{"TEMP_LOCK"}})
+	if _, err := client.Ping(); err != nil || len(http.calls) != 4 {
+		t.Fatalf("expected success on the fourth call, got %v after %d calls", err, len(http.calls))
+	}
+
+	http = &stubHTTPClient{responses: []stubResponse{{status: 409, body: `{"error_code":4001}`}, {status: 409, body: `{"code":"TEMP_LOCK"}`}}}
+	client = newTestClient(t, http, &Options{MaxRetries: 3, RetryableBodyCodes: []string{"4001"}, RetryableBodyCodeField: "error_code"})
+	var apiErr *ApiError
+	if _, err := client.Ping(); !errors.As(err, &apiErr) || apiErr.Code != CodeUnknown || apiErr.Retryable || len(http.calls) != 2 {
+		t.Fatalf("expected the numeric code field to retry once, got %v after %d calls", err, len(http.calls))
+	}
+}
+
+func TestOnExchangeSeesEveryAttempt(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 1, responses: []stubResponse{{status: 200, body: `{"status":"ok"}`}}}
+	type exchange struct {
+		req  ExchangeInfo
+		resp *HttpResponse
+		err  error
+	}
+	var exchanges []exchange
+	client := newTestClient(t, http, &Options{OnExchange: func(req ExchangeInfo, resp *HttpResponse, err error) {
+		exchanges = append(exchanges, exchange{req, resp, err})
+	}}).With(CallOptions{TraceID: "trace-1"})
+
+	if _, err := client.Progress(3, "step"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(exchanges) != 2 {
+		t.Fatalf("expected one exchange per attempt, got %d", len(exchanges))
+	}
+	first, second := exchanges[0], exchanges[1]
+	if first.req.Attempt != 1 || first.resp != nil || first.err == nil {
+		t.Fatalf("expected the failed first attempt with its error, got %#v", first)
+	}
+	want := ExchangeInfo{
+		Action:  "progress",
+		Attempt: 2,
+		Method:  "POST",
+		URL:     "https://cronbeats.io/ping/abc123de/progress/3",
+		Headers: map[string]string{"Accept": "application/json", "User-Agent": defaultUserAgent, "Content-Type": "application/json", "X-Trace-Id": "trace-1"},
+		Body:    []byte(`{"message":"step"}`),
+	}
+	second.req.Latency = 0
+	if !reflect.DeepEqual(second.req, want) || second.err != nil || second.resp.Status != 200 || second.resp.Body != `{"status":"ok"}` {
+		t.Fatalf("unexpected exchange: %#v, %#v, %v", second.req, second.resp, second.err)
+	}
+}
+
+func TestErrorMessageExtractor(t *testing.T) {
+	body := `{"message":"top","error":{"message":"nested"}}`
+	http := &stubHTTPClient{responses: []stubResponse{{status: 400, body: body}, {status: 400, body: body}}}
+
+	client := newTestClient(t, http, nil)
+	var apiErr *ApiError
+	if _, err := client.Ping(); !errors.As(err, &apiErr) || apiErr.Message != "top" {
+		t.Fatalf("expected top-level message by default, got %v", err)
+	}
+
+	client = newTestClient(t, http, &Options{ErrorMessageExtractor: func(parsed map[string]any) string {
+		nested, _ := parsed["error"].(map[string]any)
+		msg, _ := nested["message"].(string)
+		return msg
+	}})
+	if _, err := client.Ping(); !errors.As(err, &apiErr) || apiErr.Message != "nested" {
+		t.Fatalf("expected extracted message, got %v", err)
+	}
+}
+
+func TestIsSuccessBodyRejectsSoftFailures(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"result":"queued_failed","message":"backend unavailable"}`},
+			{status: 200, body: `{"result":"ok"}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{
+		MaxRetries:    1,
+		IsSuccessBody: func(parsed map[string]any) bool { return parsed["result"] == "ok" },
+	})
+
+	res, err := client.Ping()
+	if err != nil || !res.Ok || len(http.calls) != 2 {
+		t.Fatalf("expected success after retry, got %#v, %v after %d calls", res, err, len(http.calls))
+	}
+
+	http.responses = []stubResponse{{status: 200, body: `{"result":"failed","message":"backend unavailable"}`}}
+	client.cfg.maxRetries = 0
+	_, err = client.Ping()
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeServer || apiErr.Message != "backend unavailable" {
+		t.Fatalf("expected CodeServer ApiError, got %v", err)
+	}
+}
+
+func TestLastResultTracksMostRecentOutcome(t *testing.T) {
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"action":"start"}`},
+			{status: 404, body: `{"message":"Job not found"}`},
+		},
+	}
+	client := newTestClient(t, http, nil)
+
+	if res, err := client.LastResult(); res != nil || err != nil {
+		t.Fatalf("expected empty last result, got %v, %v", res, err)
+	}
+
+	_, _ = client.Start()
+	if res, err := client.LastResult(); err != nil || res.Action != "start" {
+		t.Fatalf("unexpected last result: %v, %v", res, err)
+	}
+
+	_, _ = client.Ping()
+	var apiErr *ApiError
+	if res, err := client.LastResult(); res != nil || !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound {
+		t.Fatalf("unexpected last result: %v, %v", res, err)
+	}
+}
+
+func TestStartWithScheduleSendsValidatedExpression(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.StartWithSchedule("0  2 * * sun"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/start" || http.calls[0].body != `{"schedule":"0 2 * * sun"}` {
+		t.Fatalf("unexpected call: %#v", http.calls[0])
+	}
+	var vErr *ValidationError
+	if _, err := client.StartWithSchedule("0 2 * *"); !errors.As(err, &vErr) || len(http.calls) != 1 {
+		t.Fatalf("expected an invalid expression to be rejected without a request, got %v", err)
+	}
+}
+
+func TestInitialDelayHoldsBackOnlyTheFirstRequest(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{InitialDelayMs: 250})
+	var delays []time.Duration
+	client.after = func(d time.Duration) <-chan time.Time {
+		delays = append(delays, d)
+		ch := make(chan time.Time, 1)
+		ch <- time.Time{}
+		return ch
+	}
+
+	_, _ = client.Start()
+	_, _ = client.Success()
+	if len(delays) != 1 || delays[0] != 250*time.Millisecond || len(http.calls) != 2 {
+		t.Fatalf("expected a single 250ms delay before two requests, got %v", delays)
+	}
+
+	client = newTestClient(t, http, &Options{InitialDelayMs: 60000})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var sdkErr *SdkError
+	if _, err := client.ProgressContext(ctx, nil, "step"); !errors.As(err, &sdkErr) || !errors.Is(err, context.Canceled) || len(http.calls) != 2 {
+		t.Fatalf("expected the canceled delay to abort the request, got %v", err)
+	}
+}
+
+func TestInitialDelayHoldsBackConcurrentFirstRequests(t *testing.T) {
+	http := &syncStubClient{}
+	client := newTestClient(t, http, &Options{InitialDelayMs: 250})
+	release := make(chan time.Time)
+	var timers atomic.Int32
+	client.after = func(time.Duration) <-chan time.Time {
+		timers.Add(1)
+		return release
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Ping()
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := http.count(); n != 0 {
+		t.Fatalf("expected every request to wait for the initial delay, got %d sent", n)
+	}
+	close(release)
+	wg.Wait()
+	if n := http.count(); n != 5 || timers.Load() != 1 {
+		t.Fatalf("expected 5 requests after a single delay, got %d requests and %d timers", n, timers.Load())
+	}
+}
+
+func TestOnOverdueFiresForOverdueResponses(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"next_expected":"2026-02-25 11:59:30"}`},
+			{status: 200, body: `{"next_expected":"2026-02-25 11:58:00"}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	var overdue []string
+	client := newTestClient(t, http, &Options{ClockSkewTolerance: time.Minute, OnOverdue: func(res *PingSuccess) {
+		overdue = append(overdue, *res.NextExpected)
+	}})
+	client.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(overdue) != 1 || overdue[0] != "2026-02-25 11:58:00" {
+		t.Fatalf("expected only the response beyond the tolerance to be overdue, got %v", overdue)
+	}
+	if client.IsOverdue(nil) {
+		t.Fatalf("expected a nil result not to be overdue")
+	}
+}
+
+func TestClockSkewToleranceAppliesToTimeChecks(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 200, body: `{"next_expected":"2026-02-25 11:59:30"}`},
+			{status: 200, body: `{"next_expected":"2026-02-25 11:58:00"}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{ClockSkewTolerance: time.Minute})
+	client.now = func() time.Time { return now }
+
+	if err := client.PingChecked(); err != nil {
+		t.Fatalf("expected 30s overdue to be within tolerance, got %v", err)
+	}
+	var hErr *HealthError
+	if err := client.PingChecked(); !errors.As(err, &hErr) {
+		t.Fatalf("expected 2m overdue to be reported, got %v", err)
+	}
+
+	if _, err := client.PingAt("2026-02-25T12:00:45Z"); err != nil {
+		t.Fatalf("expected timestamp 45s ahead to be accepted, got %v", err)
+	}
+	var vErr *ValidationError
+	if _, err := client.PingAt("2026-02-25T12:01:30Z"); !errors.As(err, &vErr) {
+		t.Fatalf("expected timestamp 90s ahead to be rejected, got %v", err)
+	}
+}
+
+func TestProgressIncrementAdvancesCounter(t *testing.T) {
+	http := &syncStubClient{}
+	client := newTestClient(t, http, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.ProgressIncrement(5, "chunk done")
+		}()
+	}
+	wg.Wait()
+
+	if client.CurrentProgress() != 50 || http.count() != 10 {
+		t.Fatalf("expected counter 50 after 10 calls, got %d after %d calls", client.CurrentProgress(), http.count())
+	}
+
+	if _, err := client.ProgressIncrement(-1, ""); err == nil {
+		t.Fatal("expected validation error for negative delta")
+	}
+}
+
+func TestProgressFractionEstimatesRemainingTime(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 5})
+	current := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	if _, err := client.ProgressFraction(1, 4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/progress/25" || http.calls[0].body != `{"current":1,"message":"","total":4}` {
+		t.Fatalf("expected no ETA before Start, got %#v", http.calls[0])
+	}
+
+	_, _ = client.Start()
+	current = current.Add(30 * time.Second)
+	if _, err := client.ProgressFraction(1, 4, "héllo wörld"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[2].body; got != `{"current":1,"eta_ms":90000,"message":"héllo","total":4}` {
+		t.Fatalf("expected a 90s ETA and a fitted note, got %s", got)
+	}
+	if _, err := client.ProgressFraction(0, 4); err != nil || strings.Contains(http.calls[3].body, "eta_ms") {
+		t.Fatalf("expected no ETA with nothing done, got %s, %v", http.calls[3].body, err)
+	}
+
+	var vErr *ValidationError
+	for _, tc := range [][2]int{{0, 0}, {1, -1}, {5, 4}, {-1, 4}} {
+		if _, err := client.ProgressFraction(tc[0], tc[1]); !errors.As(err, &vErr) {
+			t.Fatalf("%v: expected a ValidationError, got %v", tc, err)
+		}
+	}
+}
+
+func TestRequireProgressMessage(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{RequireProgressMessage: true})
+
+	var vErr *ValidationError
+	for _, call := range []func() (*PingSuccess, error){
+		func() (*PingSuccess, error) { return client.Progress(nil) },
+		func() (*PingSuccess, error) { return client.Progress(nil, "  ") },
+		func() (*PingSuccess, error) { return client.Progress(ProgressOptions{Message: ""}) },
+	} {
+		if _, err := call(); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError, got %v", err)
+		}
+	}
+	if _, err := client.Progress(3); err != nil {
+		t.Fatalf("expected sequence-only progress to be allowed, got %v", err)
+	}
+	if _, err := client.Progress(nil, "working"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(http.calls))
+	}
+
+	if _, err := newTestClient(t, http, nil).Progress(nil); err != nil {
+		t.Fatalf("expected empty messages to be allowed by default, got %v", err)
+	}
+}
+
+func TestSetProgressFloor(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if err := client.SetProgressFloor(40); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.ProgressIncrement(1, "resumed"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/progress/41" {
+		t.Fatalf("expected seq above the floor, got %s", http.calls[0].url)
+	}
+	if err := client.SetProgressFloor(10); err != nil || client.CurrentProgress() != 41 {
+		t.Fatalf("expected a lower floor to keep the counter, got %d (%v)", client.CurrentProgress(), err)
+	}
+
+	var vErr *ValidationError
+	if err := client.SetProgressFloor(-1); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestDefaultEndStatus(t *testing.T) {
+	fail := "fail"
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{DefaultEndStatus: &fail})
+	if _, err := client.End(""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/end/fail" {
+		t.Fatalf("unexpected url: %s", http.calls[0].url)
+	}
+
+	required := ""
+	strict := newTestClient(t, &stubHTTPClient{}, &Options{DefaultEndStatus: &required})
+	var vErr *ValidationError
+	if _, err := strict.End("  "); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for blank status, got %v", err)
+	}
+	if _, err := strict.End("success"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bogus := "maybe"
+	if _, err := NewPingClient("abc123de", &Options{DefaultEndStatus: &bogus}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for invalid default, got %v", err)
+	}
+}
+
+func TestValidateJobKey(t *testing.T) {
+	if err := ValidateJobKey("abc123de"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, key := range []string{"", "abc123d", "abc123def", "abc-23de"} {
+		var vErr *ValidationError
+		if err := ValidateJobKey(key); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestMaxConcurrentRequestsCapsInFlight(t *testing.T) {
+	probe := &concurrencyProbe{}
+	client := newTestClient(t, probe, &Options{MaxConcurrentRequests: 2})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Ping(); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := probe.peak.Load(); peak > 2 {
+		t.Fatalf("expected at most 2 requests in flight, peak was %d", peak)
+	}
+	if n := client.InFlight(); n != 0 {
+		t.Fatalf("expected nothing in flight, got %d", n)
+	}
+}
+
+func TestMaxConcurrentRequestsHonorsContext(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{MaxConcurrentRequests: 1})
+	client.cfg.slots <- struct{}{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.requestContext(ctx, "ping", route{action: "ping"}, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(http.calls) != 0 {
+		t.Fatalf("expected no request to be sent, got %d", len(http.calls))
+	}
+}
+
+func TestPreserveNumbersKeepsLargeIntegers(t *testing.T) {
+	body := `{"run_id":9007199254740993,"processing_time_ms":4.5}`
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: body}, {status: 200, body: body}}}
+
+	client := newTestClient(t, http, &Options{PreserveNumbers: true})
+	res, err := client.Ping()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id, ok := res.RawInt("run_id"); !ok || id != 9007199254740993 {
+		t.Fatalf("expected exact run_id, got %d (%v)", id, ok)
+	}
+	if res.ProcessingTimeMs != 4.5 {
+		t.Fatalf("unexpected processing time: %v", res.ProcessingTimeMs)
+	}
+
+	client = newTestClient(t, http, nil)
+	res, err = client.Ping()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := res.Raw["run_id"].(float64); !ok {
+		t.Fatalf("expected float64 by default, got %T", res.Raw["run_id"])
+	}
+	if _, ok := res.RawInt("processing_time_ms"); ok {
+		t.Fatalf("expected fractional value to be rejected")
+	}
+}
+
+func TestFailWithErrorRecordsReasonAndType(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 10})
+
+	if _, err := client.FailWithError(&ValidationError{Message: "input file is missing"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.FailWithError(nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal([]byte(http.calls[0].body), &body); err != nil {
+		t.Fatalf("unexpected body: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/end/fail" || body["reason"] != "input file" || body["error_type"] != "*cronbeatsgo.ValidationError" {
+		t.Fatalf("unexpected request: %s %v", http.calls[0].url, body)
+	}
+	if http.calls[1].url != "https://cronbeats.io/ping/abc123de/end/fail" || http.calls[1].body != "" {
+		t.Fatalf("expected plain fail for nil error, got %s %q", http.calls[1].url, http.calls[1].body)
+	}
+}
+
+func TestFailWithErrorOverflowError(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 10, ProgressOverflow: OverflowError})
+
+	var vErr *ValidationError
+	if _, err := client.FailWithError(errors.New("input file is missing")); !errors.As(err, &vErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(http.calls) != 0 {
+		t.Fatalf("expected nothing sent, got %d calls", len(http.calls))
+	}
+}
+
+func TestURLBuilderControlsRequestURLs(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{
+		BaseURL: "https://edge.example.com",
+		URLBuilder: func(baseURL string, jobKey string, action string, seq *int) string {
+			u := fmt.Sprintf("%s/v2/jobs/%s?event=%s", baseURL, jobKey, action)
+			if seq != nil {
+				u += fmt.Sprintf("&seq=%d", *seq)
+			}
+			return u
+		},
+	})
+
+	_, _ = client.Ping()
+	_, _ = client.Progress(3, "step")
+	_, _ = client.Fail()
+
+	want := []string{
+		"https://edge.example.com/v2/jobs/abc123de?event=ping",
+		"https://edge.example.com/v2/jobs/abc123de?event=progress&seq=3",
+		"https://edge.example.com/v2/jobs/abc123de?event=end/fail",
+	}
+	for i, call := range http.calls {
+		if call.url != want[i] {
+			t.Fatalf("call %d: expected %s, got %s", i, want[i], call.url)
+		}
+	}
+}
+
+func TestEndpointMap(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{PathPrefix: "/v1"})
+	want := map[string]string{
+		"ping":     "https://cronbeats.io/v1/ping/abc123de",
+		"start":    "https://cronbeats.io/v1/ping/abc123de/start",
+		"skip":     "https://cronbeats.io/v1/ping/abc123de/skip",
+		"end":      "https://cronbeats.io/v1/ping/abc123de/end/{status}",
+		"progress": "https://cronbeats.io/v1/ping/abc123de/progress/{seq}",
+	}
+	if got := client.EndpointMap(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected endpoint map: %v", got)
+	}
+
+	client = newTestClient(t, &stubHTTPClient{}, &Options{
+		URLBuilder: func(baseURL string, jobKey string, action string, seq *int) string {
+			u := fmt.Sprintf("%s/jobs/%s?event=%s", baseURL, jobKey, action)
+			if seq != nil {
+				u += fmt.Sprintf("&seq=%d", *seq)
+			}
+			return u
+		},
+	})
+	if got := client.EndpointMap()["progress"]; got != "https://cronbeats.io/jobs/abc123de?event=progress&seq={seq}" {
+		t.Fatalf("unexpected progress template: %s", got)
+	}
+}
+
+func TestDefaultURLBuilder(t *testing.T) {
+	seq := 7
+	cases := map[string]string{
+		DefaultURLBuilder("https://cronbeats.io", "abc123de", "ping", nil):        "https://cronbeats.io/ping/abc123de",
+		DefaultURLBuilder("https://cronbeats.io", "abc123de", "start", nil):       "https://cronbeats.io/ping/abc123de/start",
+		DefaultURLBuilder("https://cronbeats.io", "abc123de", "progress", &seq):   "https://cronbeats.io/ping/abc123de/progress/7",
+		DefaultURLBuilder("https://cronbeats.io", "abc123de", "end/success", nil): "https://cronbeats.io/ping/abc123de/end/success",
+	}
+	for got, want := range cases {
+		if got != want {
+			t.Fatalf("expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestNetHTTPClientResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		if r.URL.Path == "/ping/abc123de/start" {
+			select {
+			case <-release:
+			case <-time.After(2 * time.Second):
+			}
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	transport := &NetHTTPClient{ConnectTimeoutMs: 1000, ResponseHeaderTimeoutMs: 50}
+	client, err := NewPingClient("abc123de", &Options{BaseURL: server.URL, HTTPClient: transport, TimeoutMs: 5000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("expected a prompt response to succeed, got %v", err)
+	}
+
+	started := time.Now()
+	var apiErr *ApiError
+	if _, err := client.With(CallOptions{NoRetry: true}).Start(); !errors.As(err, &apiErr) || apiErr.Code != CodeNetwork {
+		t.Fatalf("expected network error from a slow response, got %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("expected the response header timeout to fire well before TimeoutMs, took %v", elapsed)
+	}
+}
+
+func TestWarmupOpensConnectionToBaseURL(t *testing.T) {
+	var heads, conns atomic.Int32
+	server := httptest.NewUnstartedServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		if r.Method == stdhttp.MethodHead {
+			heads.Add(1)
+			w.WriteHeader(stdhttp.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state stdhttp.ConnState) {
+		if state == stdhttp.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client, err := NewPingClient("abc123de", &Options{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected warmup error: %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if heads.Load() != 1 || conns.Load() != 1 {
+		t.Fatalf("expected one HEAD over one reused connection, got %d HEADs and %d connections", heads.Load(), conns.Load())
+	}
+
+	server.Close()
+	if err := client.Warmup(context.Background()); err == nil {
+		t.Fatalf("expected warmup against a closed server to report an error")
+	}
+}
+
+func TestRedirectsAreReportedUnlessFollowed(t *testing.T) {
+	server := httptest.NewServer(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		if r.URL.Path == "/moved" {
+			_, _ = w.Write([]byte(`{"message":"ok"}`))
+			return
+		}
+		stdhttp.Redirect(w, r, "/moved", stdhttp.StatusFound)
+	}))
+	defer server.Close()
+
+	client, err := NewPingClient("abc123de", &Options{BaseURL: server.URL, HTTPClient: &NetHTTPClient{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.Ping()
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeRedirect || apiErr.Retryable {
+		t.Fatalf("expected a non-retryable redirect error, got %v", err)
+	}
+	if apiErr.Message != "unexpected redirect to /moved" || *apiErr.HTTPStatus != 302 {
+		t.Fatalf("expected the redirect target and status, got %q %d", apiErr.Message, *apiErr.HTTPStatus)
+	}
+
+	client, err = NewPingClient("abc123de", &Options{BaseURL: server.URL, HTTPClient: &NetHTTPClient{FollowRedirects: true}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("expected the redirect to be followed, got %v", err)
+	}
+}
+
+func TestPathPrefixJoinsWithoutDuplicateSlashes(t *testing.T) {
+	cases := []struct {
+		baseURL string
+		prefix  string
+		want    string
+	}{
+		{"https://cronbeats.io", "", "https://cronbeats.io/ping/abc123de"},
+		{"https://cronbeats.io/", "", "https://cronbeats.io/ping/abc123de"},
+		{"https://cronbeats.io", "/api/v1/", "https://cronbeats.io/api/v1/ping/abc123de"},
+		{"https://cronbeats.io/", "api/v1", "https://cronbeats.io/api/v1/ping/abc123de"},
+		{"https://proxy.local/cronbeats/", "//v2//", "https://proxy.local/cronbeats/v2/ping/abc123de"},
+		{"https://proxy.local/cronbeats", "", "https://proxy.local/cronbeats/ping/abc123de"},
+	}
+	for _, tc := range cases {
+		http := &stubHTTPClient{}
+		client := newTestClient(t, http, &Options{BaseURL: tc.baseURL, PathPrefix: tc.prefix})
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if http.calls[0].url != tc.want {
+			t.Fatalf("base %q prefix %q: expected %s, got %s", tc.baseURL, tc.prefix, tc.want, http.calls[0].url)
+		}
+	}
+
+	http := &stubHTTPClient{networkFailures: 1}
+	client := newTestClient(t, http, &Options{MaxRetries: 1, PathPrefix: "/v1/", FallbackBaseURLs: []string{"https://backup.cronbeats.io/"}})
+	if _, err := client.Progress(2, "step"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[1].url != "https://backup.cronbeats.io/v1/ping/abc123de/progress/2" {
+		t.Fatalf("unexpected fallback url: %s", http.calls[1].url)
+	}
+}
+
+func TestStartWithExpectedDuration(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.StartWithExpectedDuration(90 * time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/start" || http.calls[0].body != `{"expected_duration_ms":90000}` {
+		t.Fatalf("unexpected request: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.StartWithExpectedDuration(0); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestEndWithCounts(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.EndWithCounts("fail", map[string]int{"succeeded": 8, "failed": 2, "skipped": 0}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/end/fail" || http.calls[0].body != `{"counts":{"failed":2,"skipped":0,"succeeded":8}}` {
+		t.Fatalf("unexpected request: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.EndWithCounts("success", map[string]int{"failed": -1}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := client.EndWithCounts("success", map[string]int{" ": 1}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected invalid counts not to be sent, got %d calls", len(http.calls))
+	}
+}
+
+func TestProgressContextSkipsNearDeadline(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	res, err := client.ProgressContext(ctx, 1, "almost done")
+	if err != nil || res.Action != "skipped" || res.Ok {
+		t.Fatalf("expected skipped result, got %+v, %v", res, err)
+	}
+	if len(http.calls) != 0 {
+		t.Fatalf("expected nothing sent, got %d calls", len(http.calls))
+	}
+
+	client = newTestClient(t, http, &Options{ProgressSkipThresholdMs: 100})
+	if res, err := client.ProgressContext(ctx, 1, "almost done"); err != nil || res.Action == "skipped" {
+		t.Fatalf("expected update to be sent, got %+v, %v", res, err)
+	}
+	if _, err := client.ProgressContext(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(http.calls))
+	}
+}
+
+func TestProgressContextUsesWallClockForDeadline(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+	client.now = func() time.Time { return time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if res, err := client.ProgressContext(ctx, 1, "almost done"); err != nil || res.Action != "skipped" {
+		t.Fatalf("expected the deadline to be measured against the wall clock, got %+v, %v", res, err)
+	}
+}
+
+func TestSkip(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{}`}, {status: 200, body: `{"action":"skipped"}`}}}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 10})
+
+	res, err := client.Skip("")
+	if err != nil || res.Action != "skip" || http.calls[0].url != "https://cronbeats.io/ping/abc123de/skip" || http.calls[0].body != "" {
+		t.Fatalf("unexpected skip: %+v, %v, %+v", res, err, http.calls[0])
+	}
+	res, err = client.Skip("upstream data not ready")
+	if err != nil || res.Action != "skipped" || http.calls[1].body != `{"reason":"upstream d"}` {
+		t.Fatalf("unexpected skip with reason: %+v, %v, %s", res, err, http.calls[1].body)
+	}
+}
+
+func TestStartScheduled(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+	now := time.Date(2026, 2, 25, 12, 10, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	if _, err := client.StartScheduled(now.Add(-10 * time.Minute)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"scheduled_at":"2026-02-25T12:00:00Z","started_at":"2026-02-25T12:10:00Z"}`
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/start" || http.calls[0].body != want {
+		t.Fatalf("unexpected request: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.StartScheduled(now.Add(time.Hour)); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for a future schedule, got %v", err)
+	}
+	if _, err := client.StartScheduled(time.Time{}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for a zero time, got %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected invalid calls not to be sent, got %d calls", len(http.calls))
+	}
+}
+
+func TestPingWithBody(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 5})
+
+	if _, err := client.PingWithBody(map[string]any{"version": "1.4.0", "message": "warming caches"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de" || http.calls[0].body != `{"message":"warmi","version":"1.4.0"}` {
+		t.Fatalf("unexpected request: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.PingWithBody(map[string]any{"bad": make(chan int)}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected invalid body not to be sent")
+	}
+}
+
+func TestMapError(t *testing.T) {
+	cases := []struct {
+		status    int
+		code      ApiErrorCode
+		retryable bool
+	}{
+		{400, CodeValidation, false},
+		{404, CodeNotFound, false},
+		{429, CodeRateLimit, true},
+		{500, CodeServer, true},
+		{503, CodeServer, true},
+		{403, CodeUnknown, false},
+	}
+	for _, tc := range cases {
+		code, retryable := MapError(tc.status)
+		if code != tc.code || retryable != tc.retryable {
+			t.Fatalf("status %d: got %s/%v, want %s/%v", tc.status, code, retryable, tc.code, tc.retryable)
+		}
+	}
+}
+
+func TestOnFirstSuccessFiresOnce(t *testing.T) {
+	var fired atomic.Int32
+	var first *PingSuccess
+	http := &syncStubClient{response: func(call int) *HttpResponse {
+		if call == 1 {
+			return &HttpResponse{Status: 404, Body: `{}`, Headers: map[string]string{}}
+		}
+		return &HttpResponse{Status: 200, Body: `{"action":"ping"}`, Headers: map[string]string{}}
+	}}
+	client := newTestClient(t, http, &Options{OnFirstSuccess: func(res *PingSuccess) {
+		fired.Add(1)
+		first = res
+	}})
+
+	if _, err := client.Ping(); err == nil {
+		t.Fatalf("expected the first call to fail")
+	}
+	if fired.Load() != 0 {
+		t.Fatalf("OnFirstSuccess must not fire on failure")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = client.Ping()
+		}()
+	}
+	wg.Wait()
+	_, _ = client.With(CallOptions{}).Start()
+
+	if fired.Load() != 1 || first == nil || !first.Ok {
+		t.Fatalf("expected exactly one call with a success, got %d (%v)", fired.Load(), first)
+	}
+}
+
+func TestProgressOverflowError(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 5, ProgressOverflow: OverflowError})
+
+	var vErr *ValidationError
+	if _, err := client.Progress(1, "too long"); !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "5 characters") {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := client.EndWithMessage("success", "too long"); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := client.Progress(1, "short"); err != nil {
+		t.Fatalf("unexpected error for a message within the limit: %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected only the fitting message to be sent, got %d calls", len(http.calls))
+	}
+
+	if _, err := NewPingClient("abc123de", &Options{ProgressOverflow: "drop"}); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for unknown mode, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for everything the client does: timestamps,
+// overdue and quiet-hours checks, rate limiting, retry backoff, heartbeat
+// and flusher intervals. Set Options.Clock to a fake to make them
+// deterministic in tests.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel that receives the time once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// WithClock sets Options.Clock.
+func (o *Options) WithClock(clock Clock) *Options {
+	o.Clock = clock
+	return o
+}
+
+// sleepOn blocks for d on clock.
+func sleepOn(clock Clock) func(d time.Duration) {
+	return func(d time.Duration) {
+		if d > 0 {
+			<-clock.After(d)
+		}
+	}
+}
+
+// sleepContext waits d on the client's clock, returning ctx.Err() as soon as
+// ctx ends. A context that can never end takes the plain sleep.
+func (c *clientCore) sleepContext(ctx context.Context, d time.Duration) error {
+	if ctx.Done() == nil {
+		c.sleep(d)
+		return nil
+	}
+	if err := ctx.Err(); err != nil || d <= 0 {
+		return err
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.after(d):
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when waited on: After moves the time forward by d
+// and fires immediately.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.waits = append(f.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- f.now
+	return ch
+}
+
+func TestClockDrivesRetriesLogsAndQuietHours(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 21, 59, 59, 0, time.UTC)}
+	var logs bytes.Buffer
+	http := &stubHTTPClient{networkFailures: 1}
+	client, err := NewPingClient("abc123de", (&Options{
+		HTTPClient:     http,
+		RetryBackoffMs: 1000,
+		RetryJitterMs:  1,
+		QuietHours:     []TimeRange{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+	}).WithJSONLogger(&logs).WithClock(clock))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clock.waits) != 1 || clock.waits[0] < time.Second {
+		t.Fatalf("expected the retry backoff to wait on the clock, got %v", clock.waits)
+	}
+	if !strings.Contains(logs.String(), `"time":"2026-03-01T21:59:59Z"`) {
+		t.Fatalf("expected log times from the clock, got %s", logs.String())
+	}
+
+	res, err := client.Ping()
+	if err != nil || res.Action != "skipped" {
+		t.Fatalf("expected the advanced clock to fall in quiet hours, got %#v, %v", res, err)
+	}
+	if len(http.calls) != 2 {
+		t.Fatalf("expected no request during quiet hours, got %d calls", len(http.calls))
+	}
+}
@@ -0,0 +1,105 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// fileConfig is the on-disk shape read by NewPingClientFromFile:
+//
+//	{"job_key": "abc123de", "base_url": "https://cronbeats.io", "timeout_ms": 3000}
+type fileConfig struct {
+	JobKey            string   `json:"job_key"`
+	BaseURL           string   `json:"base_url"`
+	FallbackBaseURLs  []string `json:"fallback_base_urls"`
+	TimeoutMs         int      `json:"timeout_ms"`
+	MaxRetries        int      `json:"max_retries"`
+	RetryBackoffMs    int      `json:"retry_backoff_ms"`
+	RetryJitterMs     int      `json:"retry_jitter_ms"`
+	RetryMinBackoffMs int      `json:"retry_min_backoff_ms"`
+	UserAgent         string   `json:"user_agent"`
+}
+
+// NewPingClientFromFile builds a client from a JSON config file holding the
+// job key and option overrides. Unknown keys are rejected so typos surface
+// instead of being silently ignored.
+func NewPingClientFromFile(path string) (*PingClient, error) {
+	jobKey, options, err := loadConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewPingClient(jobKey, &options)
+	if err != nil {
+		var vErr *ValidationError
+		if errors.As(err, &vErr) {
+			return nil, &ValidationError{Message: fmt.Sprintf("config %s: %s", path, vErr.Message)}
+		}
+		return nil, err
+	}
+	return client, nil
+}
+
+func loadConfigFile(path string) (string, Options, error) {
+	fc, err := readConfigFile(path)
+	if err != nil {
+		return "", Options{}, err
+	}
+	if !jobKeyRegex.MatchString(fc.JobKey) {
+		return "", Options{}, &ValidationError{Message: fmt.Sprintf(`config %s: field "job_key" must be exactly 8 Base62 characters`, path)}
+	}
+	return fc.JobKey, fc.options(), nil
+}
+
+func readConfigFile(path string) (fileConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fileConfig{}, &SdkError{Message: "failed to read config file", Cause: err}
+	}
+
+	var fc fileConfig
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fc); err != nil {
+		return fileConfig{}, &ValidationError{Message: fmt.Sprintf("config %s: %s", path, describeConfigError(raw, err))}
+	}
+	if dec.More() {
+		return fileConfig{}, &ValidationError{Message: fmt.Sprintf("config %s: unexpected data after the top-level object", path)}
+	}
+	return fc, nil
+}
+
+func (fc fileConfig) options() Options {
+	return Options{
+		BaseURL:           fc.BaseURL,
+		FallbackBaseURLs:  fc.FallbackBaseURLs,
+		TimeoutMs:         fc.TimeoutMs,
+		MaxRetries:        fc.MaxRetries,
+		RetryBackoffMs:    fc.RetryBackoffMs,
+		RetryJitterMs:     fc.RetryJitterMs,
+		RetryMinBackoffMs: fc.RetryMinBackoffMs,
+		UserAgent:         fc.UserAgent,
+	}
+}
+
+func describeConfigError(raw []byte, err error) string {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("field %q must be a %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	case errors.As(err, &syntaxErr):
+		line := 1 + bytes.Count(raw[:syntaxErr.Offset], []byte("\n"))
+		return fmt.Sprintf("invalid JSON on line %d: %s", line, syntaxErr.Error())
+	case errors.Is(err, io.EOF):
+		return "file is empty"
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		return "unknown field " + strings.TrimPrefix(err.Error(), "json: unknown field ")
+	default:
+		return err.Error()
+	}
+}
@@ -0,0 +1,51 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cronbeats.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestNewPingClientFromFile(t *testing.T) {
+	path := writeConfig(t, `{"job_key":"abc123de","base_url":"https://eu.cronbeats.io/","timeout_ms":3000}`)
+
+	client, err := NewPingClientFromFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := client.config()
+	if client.jobKey != "abc123de" || cfg.baseURL != "https://eu.cronbeats.io" || cfg.timeoutMs != 3000 {
+		t.Fatalf("unexpected config: key=%s base=%s timeout=%d", client.jobKey, cfg.baseURL, cfg.timeoutMs)
+	}
+}
+
+func TestNewPingClientFromFileReportsOffendingField(t *testing.T) {
+	cases := map[string]string{
+		`{"job_key":"abc123de","timeout_ms":"fast"}`: `field "timeout_ms"`,
+		`{"job_key":"abc123de","timeot_ms":10}`:      `unknown field "timeot_ms"`,
+		`{"job_key":"nope"}`:                         `field "job_key"`,
+		"{\n\"job_key\": \"abc123de\",\n}":           "line 3",
+		`{"job_key":"abc123de","max_retries":-1}`:    "MaxRetries",
+	}
+	for content, want := range cases {
+		_, err := NewPingClientFromFile(writeConfig(t, content))
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %s, got %v", content, err)
+		}
+		if !strings.Contains(vErr.Message, want) {
+			t.Fatalf("expected error for %s to mention %q, got %q", content, want, vErr.Message)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package cronbeatsgo
+
+import "context"
+
+type contextKey int
+
+const (
+	jobKeyContextKey contextKey = iota
+	actionContextKey
+	replayContextKey
+)
+
+// JobKeyFromContext returns the job key of the ping a transport is sending.
+func JobKeyFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(jobKeyContextKey).(string)
+	return v, ok
+}
+
+// ActionFromContext returns the action ("ping", "start", ...) of the ping a
+// transport is sending.
+func ActionFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(actionContextKey).(string)
+	return v, ok
+}
+
+func withRequestInfo(ctx context.Context, jobKey string, action string) context.Context {
+	ctx = context.WithValue(ctx, jobKeyContextKey, jobKey)
+	return context.WithValue(ctx, actionContextKey, action)
+}
+
+// withReplay marks ctx as carrying a ping replayed from the offline queue.
+func withReplay(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replayContextKey, true)
+}
+
+func isReplay(ctx context.Context) bool {
+	replayed, _ := ctx.Value(replayContextKey).(bool)
+	return replayed
+}
@@ -0,0 +1,51 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"testing"
+)
+
+type contextCaptureClient struct {
+	jobKeys []string
+	actions []string
+}
+
+func (c *contextCaptureClient) Request(_ string, _ string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	panic("Request must not be used when RequestContext is available")
+}
+
+func (c *contextCaptureClient) RequestContext(ctx context.Context, _ string, _ string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	jobKey, _ := JobKeyFromContext(ctx)
+	action, _ := ActionFromContext(ctx)
+	c.jobKeys = append(c.jobKeys, jobKey)
+	c.actions = append(c.actions, action)
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestContextCarriesJobKeyAndAction(t *testing.T) {
+	http := &contextCaptureClient{}
+	client := newTestClient(t, http, nil)
+
+	if _, err := client.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Progress(nil, "halfway"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if http.jobKeys[0] != "abc123de" || http.jobKeys[1] != "abc123de" {
+		t.Fatalf("unexpected job keys: %v", http.jobKeys)
+	}
+	if http.actions[0] != "start" || http.actions[1] != "progress" {
+		t.Fatalf("unexpected actions: %v", http.actions)
+	}
+}
+
+func TestContextAccessorsOnEmptyContext(t *testing.T) {
+	if _, ok := JobKeyFromContext(context.Background()); ok {
+		t.Fatal("expected no job key on empty context")
+	}
+	if _, ok := ActionFromContext(context.Background()); ok {
+		t.Fatal("expected no action on empty context")
+	}
+}
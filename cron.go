@@ -0,0 +1,102 @@
+package cronbeatsgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// cronField is the allowed range and value names of one cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // names[i] stands for min+i
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// normalizeCron checks that expr is a standard five-field cron expression or
+// one of the @yearly/@monthly/@weekly/@daily/@hourly macros, and returns it
+// with fields separated by single spaces. Each field is "*", a value, a range
+// "a-b", either followed by a "/step", or a comma-separated list of those.
+// Month and weekday names such as "jan" and "mon" are accepted.
+func normalizeCron(expr string) (string, error) {
+	fields := strings.Fields(expr)
+	if len(fields) == 1 && strings.HasPrefix(fields[0], "@") {
+		macro := strings.ToLower(fields[0])
+		if !cronMacros[macro] {
+			return "", &ValidationError{Message: fmt.Sprintf("unknown cron macro %q.", fields[0])}
+		}
+		return macro, nil
+	}
+	if len(fields) != len(cronFields) {
+		return "", &ValidationError{Message: fmt.Sprintf("cron expression must have 5 fields, got %d.", len(fields))}
+	}
+	for i, field := range fields {
+		if err := cronFields[i].check(field); err != nil {
+			return "", &ValidationError{Message: fmt.Sprintf("cron %s field %q: %s.", cronFields[i].name, field, err)}
+		}
+	}
+	return strings.Join(fields, " "), nil
+}
+
+func (f cronField) check(field string) error {
+	for _, part := range strings.Split(field, ",") {
+		spec, step, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			n, err := strconv.Atoi(step)
+			if err != nil || n <= 0 {
+				return fmt.Errorf("step %q must be a positive integer", step)
+			}
+		}
+		if spec == "*" {
+			continue
+		}
+		low, high, isRange := strings.Cut(spec, "-")
+		lo, err := f.value(low)
+		if err != nil {
+			return err
+		}
+		if !isRange {
+			if hasStep {
+				return fmt.Errorf("step needs \"*\" or a range, got %q", spec)
+			}
+			continue
+		}
+		hi, err := f.value(high)
+		if err != nil {
+			return err
+		}
+		if lo > hi {
+			return fmt.Errorf("range %q is reversed", spec)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
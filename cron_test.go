@@ -0,0 +1,44 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizeCronAcceptsStandardSyntax(t *testing.T) {
+	cases := map[string]string{
+		"*/15 * * * *":               "*/15 * * * *",
+		"  0  3 * *   MON-FRI ":      "0 3 * * MON-FRI",
+		"0,30 8-18/2 1,15 jan-jun 7": "0,30 8-18/2 1,15 jan-jun 7",
+		"@Daily":                     "@daily",
+	}
+	for expr, want := range cases {
+		got, err := normalizeCron(expr)
+		if err != nil || got != want {
+			t.Fatalf("%q: expected %q, got %q, %v", expr, want, got, err)
+		}
+	}
+}
+
+func TestNormalizeCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5/10 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@every5m",
+	} {
+		var vErr *ValidationError
+		if _, err := normalizeCron(expr); !errors.As(err, &vErr) {
+			t.Fatalf("%q: expected a ValidationError, got %v", expr, err)
+		}
+	}
+}
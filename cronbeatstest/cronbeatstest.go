@@ -0,0 +1,37 @@
+// Package cronbeatstest provides helpers for tests of code that uses the
+// CronBeats SDK.
+package cronbeatstest
+
+import (
+	"math/rand"
+	"sync"
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	rngMu sync.Mutex
+	rng   = rand.New(rand.NewSource(rand.Int63()))
+)
+
+// GenerateJobKey returns a random, well-formed job key for fixtures. Keys are
+// not guaranteed to be unique.
+func GenerateJobKey() string {
+	rngMu.Lock()
+	defer rngMu.Unlock()
+	return generateJobKey(rng)
+}
+
+// GenerateJobKeyFrom is GenerateJobKey drawing from r, for reproducible
+// fixtures.
+func GenerateJobKeyFrom(r *rand.Rand) string {
+	return generateJobKey(r)
+}
+
+func generateJobKey(r *rand.Rand) string {
+	key := make([]byte, 8)
+	for i := range key {
+		key[i] = base62[r.Intn(len(base62))]
+	}
+	return string(key)
+}
@@ -0,0 +1,28 @@
+package cronbeatstest
+
+import (
+	"math/rand"
+	"testing"
+
+	cronbeatsgo "github.com/cronbeats/cronbeats-go"
+)
+
+func TestGenerateJobKeyIsAlwaysValid(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		key := GenerateJobKey()
+		if err := cronbeatsgo.ValidateJobKey(key); err != nil {
+			t.Fatalf("generated invalid key %q: %v", key, err)
+		}
+	}
+}
+
+func TestGenerateJobKeyFromIsReproducible(t *testing.T) {
+	a := GenerateJobKeyFrom(rand.New(rand.NewSource(7)))
+	b := GenerateJobKeyFrom(rand.New(rand.NewSource(7)))
+	if a != b {
+		t.Fatalf("expected the same key for the same seed, got %q and %q", a, b)
+	}
+	if _, err := cronbeatsgo.NewPingClient(a, nil); err != nil {
+		t.Fatalf("generated key rejected by NewPingClient: %v", err)
+	}
+}
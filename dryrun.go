@@ -0,0 +1,39 @@
+package cronbeatsgo
+
+// dryRunResponse is what every request returns under Options.DryRun.
+var dryRunResponse = HttpResponse{Status: 200, Body: `{}`}
+
+func (c *PingClient) recordDryRun(req *OutgoingRequest) *HttpResponse {
+	recorded := OutgoingRequest{
+		Method:  req.Method,
+		URL:     req.URL,
+		Headers: make(map[string]string, len(req.Headers)),
+		Body:    append([]byte(nil), req.Body...),
+	}
+	for key, value := range req.Headers {
+		recorded.Headers[key] = value
+	}
+
+	c.dryMu.Lock()
+	c.dryCalls = append(c.dryCalls, recorded)
+	c.dryMu.Unlock()
+
+	res := dryRunResponse
+	res.Headers = map[string]string{}
+	return &res
+}
+
+// DryRunCalls returns a copy of the requests recorded under Options.DryRun,
+// oldest first, exactly as they would have been sent.
+func (c *PingClient) DryRunCalls() []OutgoingRequest {
+	c.dryMu.Lock()
+	defer c.dryMu.Unlock()
+	return append([]OutgoingRequest(nil), c.dryCalls...)
+}
+
+// Reset clears the requests recorded under Options.DryRun.
+func (c *PingClient) Reset() {
+	c.dryMu.Lock()
+	defer c.dryMu.Unlock()
+	c.dryCalls = nil
+}
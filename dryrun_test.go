@@ -0,0 +1,57 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDryRunRecordsRequests(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{DryRun: true, UserAgent: "dry/1.0"})
+
+	if res, err := client.Ping(); err != nil || !res.Ok {
+		t.Fatalf("expected a synthetic success, got %+v, %v", res, err)
+	}
+	if _, err := client.Progress(2, "halfway"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 0 {
+		t.Fatalf("expected nothing sent, got %d calls", len(http.calls))
+	}
+
+	calls := client.DryRunCalls()
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 recorded calls, got %d", len(calls))
+	}
+	if calls[0].Method != "POST" || calls[0].URL != "https://cronbeats.io/ping/abc123de" || calls[0].Headers["User-Agent"] != "dry/1.0" || len(calls[0].Body) != 0 {
+		t.Fatalf("unexpected ping call: %+v", calls[0])
+	}
+	if calls[1].URL != "https://cronbeats.io/ping/abc123de/progress/2" || string(calls[1].Body) != `{"message":"halfway"}` || calls[1].Headers["Content-Type"] != "application/json" {
+		t.Fatalf("unexpected progress call: %+v", calls[1])
+	}
+
+	if err := client.Warmup(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.History(context.Background(), 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls := client.DryRunCalls(); len(calls) != 4 || calls[2].Method != "HEAD" || calls[3].Method != "GET" || len(http.calls) != 0 {
+		t.Fatalf("expected warmup and history to be recorded, got %+v", calls)
+	}
+
+	client.Reset()
+	if calls := client.DryRunCalls(); len(calls) != 0 {
+		t.Fatalf("expected Reset to clear calls, got %d", len(calls))
+	}
+}
+
+func TestDryRunWithHTTPClientIsStrictProblem(t *testing.T) {
+	var vErr *ValidationError
+	_, err := NewPingClient("abc123de", &Options{DryRun: true, HTTPClient: &stubHTTPClient{}, StrictConfig: true})
+	if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, "DryRun") {
+		t.Fatalf("expected strict config error, got %v", err)
+	}
+}
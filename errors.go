@@ -1,6 +1,12 @@
 package cronbeatsgo
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
 
 type ApiErrorCode string
 
@@ -11,6 +17,16 @@ const (
 	CodeServer     ApiErrorCode = "SERVER_ERROR"
 	CodeNetwork    ApiErrorCode = "NETWORK_ERROR"
 	CodeUnknown    ApiErrorCode = "UNKNOWN_ERROR"
+	// CodeMaintenance is a 503 whose body says the server is down for
+	// maintenance, or a request skipped during a maintenance window.
+	CodeMaintenance ApiErrorCode = "MAINTENANCE"
+	// CodeRedirect is a 3xx response, which the ping API never sends.
+	CodeRedirect ApiErrorCode = "REDIRECT"
+)
+
+var (
+	ErrNoResponse     = errors.New("no successful response received yet")
+	ErrNoNextExpected = errors.New("last response did not include next_expected")
 )
 
 type ValidationError struct {
@@ -37,14 +53,121 @@ func (e *SdkError) Unwrap() error {
 	return e.Cause
 }
 
+// MultiError is a failure with several causes, such as PingAny failing on
+// every endpoint. errors.Is and errors.As match any of Errors.
+type MultiError struct {
+	Message string
+	Errors  []error
+}
+
+func (e *MultiError) Error() string {
+	causes := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		if err != nil {
+			causes = append(causes, err.Error())
+		}
+	}
+	if len(causes) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(causes, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	return e.Errors
+}
+
 type ApiError struct {
 	Code       ApiErrorCode
 	HTTPStatus *int
 	Retryable  bool
 	Message    string
 	Raw        any
+	// BodyPreview holds the start of the raw response body, which is the
+	// only trace of what the server sent when the body is not valid JSON.
+	BodyPreview string
 }
 
 func (e *ApiError) Error() string {
 	return e.Message
 }
+
+// ToHTTP maps e to a status and JSON body for an application that passes
+// CronBeats failures on to its own clients. Problems with the request keep
+// their 4xx status, maintenance becomes 503, a network timeout 504 and any
+// other failure 502 Bad Gateway. The body holds "code", "message" and
+// "retryable", plus "upstream_status" when a response was received.
+func (e *ApiError) ToHTTP() (status int, body map[string]any) {
+	switch e.Code {
+	case CodeValidation:
+		status = http.StatusBadRequest
+	case CodeNotFound:
+		status = http.StatusNotFound
+	case CodeRateLimit:
+		status = http.StatusTooManyRequests
+	case CodeMaintenance:
+		status = http.StatusServiceUnavailable
+	case CodeNetwork:
+		status = http.StatusBadGateway
+		var netErr net.Error
+		if cause, ok := e.Raw.(error); ok && errors.As(cause, &netErr) && netErr.Timeout() {
+			status = http.StatusGatewayTimeout
+		}
+	default:
+		status = http.StatusBadGateway
+		if e.HTTPStatus != nil && *e.HTTPStatus >= 400 && *e.HTTPStatus < 500 {
+			status = *e.HTTPStatus
+		}
+	}
+
+	body = map[string]any{
+		"code":      string(e.Code),
+		"message":   e.Message,
+		"retryable": e.Retryable,
+	}
+	if e.HTTPStatus != nil {
+		body["upstream_status"] = *e.HTTPStatus
+	}
+	return status, body
+}
+
+// HealthError is returned by PingChecked when the ping was delivered but the
+// server's response says the job is not healthy.
+type HealthError struct {
+	Reason string
+	Result *PingSuccess
+}
+
+func (e *HealthError) Error() string {
+	return "job unhealthy: " + e.Reason
+}
+
+type RetryReason int
+
+const (
+	ReasonNetwork RetryReason = iota
+	ReasonRateLimit
+	ReasonServer
+)
+
+func (r RetryReason) String() string {
+	switch r {
+	case ReasonNetwork:
+		return "network"
+	case ReasonRateLimit:
+		return "rate_limit"
+	default:
+		return "server"
+	}
+}
+
+func retryReason(code ApiErrorCode) RetryReason {
+	switch code {
+	case CodeNetwork:
+		return ReasonNetwork
+	case CodeRateLimit:
+		return ReasonRateLimit
+	default:
+		return ReasonServer
+	}
+}
@@ -0,0 +1,68 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestApiErrorToHTTP(t *testing.T) {
+	status := func(n int) *int { return &n }
+	cases := []struct {
+		err  *ApiError
+		want int
+	}{
+		{&ApiError{Code: CodeValidation, HTTPStatus: status(400)}, 400},
+		{&ApiError{Code: CodeNotFound, HTTPStatus: status(404)}, 404},
+		{&ApiError{Code: CodeRateLimit, HTTPStatus: status(429)}, 429},
+		{&ApiError{Code: CodeServer, HTTPStatus: status(500)}, 502},
+		{&ApiError{Code: CodeMaintenance, HTTPStatus: status(503)}, 503},
+		{&ApiError{Code: CodeUnknown, HTTPStatus: status(409)}, 409},
+		{&ApiError{Code: CodeUnknown, HTTPStatus: status(302)}, 502},
+		{&ApiError{Code: CodeNetwork, Raw: context.Canceled}, 502},
+		{&ApiError{Code: CodeNetwork, Raw: &SdkError{Message: "network request failed", Cause: timeoutError{}}}, 504},
+	}
+	for _, tc := range cases {
+		if got, _ := tc.err.ToHTTP(); got != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.err.Code, tc.want, got)
+		}
+	}
+
+	_, body := (&ApiError{Code: CodeServer, HTTPStatus: status(500), Retryable: true, Message: "down"}).ToHTTP()
+	want := map[string]any{"code": "SERVER_ERROR", "message": "down", "retryable": true, "upstream_status": 500}
+	if !reflect.DeepEqual(body, want) {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	if _, body := (&ApiError{Code: CodeNetwork}).ToHTTP(); body["upstream_status"] != nil {
+		t.Fatalf("expected no upstream_status without a response, got %v", body)
+	}
+}
+
+func TestMultiErrorMatchesEachCause(t *testing.T) {
+	status := 503
+	primary := &ApiError{Code: CodeServer, HTTPStatus: &status, Message: "down"}
+	fallback := &SdkError{Message: "socket timeout", Cause: context.DeadlineExceeded}
+	err := error(&MultiError{Message: "all endpoints failed", Errors: []error{primary, fallback}})
+
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr != primary {
+		t.Fatalf("expected errors.As to find the ApiError, got %v", apiErr)
+	}
+	var sdkErr *SdkError
+	if !errors.As(err, &sdkErr) || sdkErr != fallback {
+		t.Fatalf("expected errors.As to find the SdkError, got %v", sdkErr)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, primary) {
+		t.Fatalf("expected errors.Is to match every cause")
+	}
+	if got := err.Error(); got != "all endpoints failed: down; socket timeout: context deadline exceeded" {
+		t.Fatalf("unexpected message: %q", got)
+	}
+}
@@ -0,0 +1,128 @@
+package cronbeatsgo
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event types written to Options.EventWriter.
+const (
+	EventRequest = "request"
+	EventAttempt = "attempt"
+	EventRetry   = "retry"
+	EventSuccess = "success"
+	EventFailure = "failure"
+)
+
+// StreamEvent is one line of the Options.EventWriter stream. A call produces
+// a "request" event, an "attempt" event per HTTP attempt with a "retry"
+// event before each retry, and finally "success" or "failure". On a retry
+// event Attempt is the attempt about to be made. Fields that do not apply to
+// an event are omitted.
+type StreamEvent struct {
+	Time      time.Time    `json:"time"`
+	Type      string       `json:"type"`
+	JobKey    string       `json:"job_key"`
+	Action    string       `json:"action"`
+	Attempt   int          `json:"attempt,omitempty"`
+	Status    int          `json:"status,omitempty"`
+	LatencyMs float64      `json:"latency_ms,omitempty"`
+	Code      ApiErrorCode `json:"code,omitempty"`
+	Error     string       `json:"error,omitempty"`
+	TraceID   string       `json:"trace_id,omitempty"`
+}
+
+// eventStream buffers StreamEvents as JSON lines in front of a writer. It
+// outlives option updates that keep the same writer.
+type eventStream struct {
+	w   io.Writer
+	mu  sync.Mutex
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func newEventStream(w io.Writer) *eventStream {
+	buf := bufio.NewWriter(w)
+	return &eventStream{w: w, buf: buf, enc: json.NewEncoder(buf)}
+}
+
+func (s *eventStream) write(event StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.enc.Encode(event)
+}
+
+func (s *eventStream) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Flush()
+}
+
+func (c *PingClient) emit(cfg *clientConfig, event StreamEvent) {
+	if cfg.events == nil {
+		return
+	}
+	event.Time = c.now().UTC()
+	event.JobKey = c.jobKey
+	event.TraceID = c.call.TraceID
+	cfg.events.write(event)
+}
+
+// errorEvent fills the outcome fields of event from err.
+func errorEvent(event StreamEvent, err error) StreamEvent {
+	event.Error = err.Error()
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		event.Code = apiErr.Code
+		if apiErr.HTTPStatus != nil {
+			event.Status = *apiErr.HTTPStatus
+		}
+	}
+	return event
+}
+
+func latencyMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Flush writes out the buffered Options.EventWriter stream and flushes the
+// EventWriter and AuditWriter themselves when they have a Flush() error
+// method, like *bufio.Writer. It returns the first error met writing to
+// either since the previous Flush.
+func (c *PingClient) Flush() error {
+	cfg := c.config()
+	var errs []error
+	flushed := map[io.Writer]bool{}
+	flushWriter := func(w io.Writer) {
+		if f, ok := w.(interface{ Flush() error }); ok && !flushed[w] {
+			flushed[w] = true
+			errs = append(errs, f.Flush())
+		}
+	}
+	if cfg.events != nil {
+		if err := cfg.events.flush(); err != nil {
+			errs = append(errs, &SdkError{Message: "failed to write events", Cause: err})
+		}
+		flushWriter(cfg.events.w)
+	}
+	if cfg.auditWriter != nil {
+		flushWriter(cfg.auditWriter)
+	}
+	c.writeErrMu.Lock()
+	errs = append(errs, c.writeErr)
+	c.writeErr = nil
+	c.writeErrMu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Close sends any progress buffered under Options.ProgressBatchSize, then
+// flushes like Flush so no events are lost when the process exits. The
+// client remains usable afterwards.
+func (c *PingClient) Close() error {
+	_, err := c.FlushProgress()
+	return errors.Join(err, c.Flush())
+}
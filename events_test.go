@@ -0,0 +1,124 @@
+package cronbeatsgo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func decodeEvents(t *testing.T, raw string) []StreamEvent {
+	t.Helper()
+	var events []StreamEvent
+	for _, line := range strings.Split(strings.TrimSpace(raw), "\n") {
+		var event StreamEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestEventWriterStreamsActivity(t *testing.T) {
+	var out bytes.Buffer
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 200, body: `{}`}, {status: 404, body: `{}`}}}
+	client := newTestClient(t, http, &Options{MaxRetries: 1, EventWriter: &out})
+	client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+
+	_, _ = client.With(CallOptions{TraceID: "t-1"}).Ping()
+	_, _ = client.Start()
+	if out.Len() != 0 {
+		t.Fatalf("expected events to be buffered until Close, got %q", out.String())
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := decodeEvents(t, out.String())
+	want := []struct {
+		typ     string
+		action  string
+		attempt int
+		status  int
+	}{
+		{EventRequest, "ping", 0, 0},
+		{EventAttempt, "ping", 1, 503},
+		{EventRetry, "ping", 2, 503},
+		{EventAttempt, "ping", 2, 200},
+		{EventSuccess, "ping", 0, 0},
+		{EventRequest, "start", 0, 0},
+		{EventAttempt, "start", 1, 404},
+		{EventFailure, "start", 0, 404},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d:\n%s", len(want), len(events), out.String())
+	}
+	for i, w := range want {
+		e := events[i]
+		if e.Type != w.typ || e.Action != w.action || e.Attempt != w.attempt || e.Status != w.status || e.JobKey != "abc123de" {
+			t.Fatalf("unexpected event %d: %+v", i, e)
+		}
+	}
+	if events[0].TraceID != "t-1" || events[5].TraceID != "" || events[7].Code != CodeNotFound {
+		t.Fatalf("unexpected event details: %+v", events)
+	}
+}
+
+func TestEventWriterSurvivesOptionUpdates(t *testing.T) {
+	var out bytes.Buffer
+	client := newTestClient(t, &stubHTTPClient{}, &Options{EventWriter: &out})
+
+	_, _ = client.Ping()
+	if err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = 1000 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = client.Ping()
+	_ = client.Close()
+
+	if events := decodeEvents(t, out.String()); len(events) != 6 {
+		t.Fatalf("expected events from both configurations, got %d:\n%s", len(events), out.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestCloseFlushesEventAndAuditWriters(t *testing.T) {
+	var events, audit bytes.Buffer
+	auditBuf := bufio.NewWriter(&audit)
+	client := newTestClient(t, &stubHTTPClient{}, &Options{EventWriter: &events, AuditWriter: auditBuf})
+	for i := 0; i < 3; i++ {
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if events.Len() != 0 || audit.Len() != 0 {
+		t.Fatalf("expected output to stay buffered before Close")
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if got := decodeEvents(t, events.String()); len(got) != 9 || got[8].Type != EventSuccess {
+		t.Fatalf("expected all 9 events after Close, got %d", len(got))
+	}
+	if lines := strings.Count(audit.String(), "outcome=ok"); lines != 3 {
+		t.Fatalf("expected 3 audit lines after Close, got %d:\n%s", lines, audit.String())
+	}
+}
+
+func TestFlushReturnsWriteErrors(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{EventWriter: failingWriter{}, AuditWriter: failingWriter{}})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := client.Flush()
+	var sdkErr *SdkError
+	if !errors.As(err, &sdkErr) || !strings.Contains(err.Error(), "disk full") || !strings.Contains(err.Error(), "audit line") {
+		t.Fatalf("expected the event and audit write errors, got %v", err)
+	}
+}
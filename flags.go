@@ -0,0 +1,130 @@
+package cronbeatsgo
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// setting is one configuration value that can come from a flag, an
+// environment variable or the config file.
+type setting struct {
+	name  string // flag name without the "cronbeats-" prefix
+	usage string
+	set   func(fc *fileConfig, value string) error
+}
+
+func intSetting(name string, usage string, field func(fc *fileConfig) *int) setting {
+	return setting{name: name, usage: usage, set: func(fc *fileConfig, value string) error {
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("must be an integer, got %q", value)
+		}
+		*field(fc) = n
+		return nil
+	}}
+}
+
+func stringSetting(name string, usage string, field func(fc *fileConfig) *string) setting {
+	return setting{name: name, usage: usage, set: func(fc *fileConfig, value string) error {
+		*field(fc) = strings.TrimSpace(value)
+		return nil
+	}}
+}
+
+var settings = []setting{
+	stringSetting("job-key", "job key to ping", func(fc *fileConfig) *string { return &fc.JobKey }),
+	stringSetting("base-url", "base URL of the CronBeats API", func(fc *fileConfig) *string { return &fc.BaseURL }),
+	{name: "fallback-base-urls", usage: "comma-separated fallback base URLs", set: func(fc *fileConfig, value string) error {
+		fc.FallbackBaseURLs = nil
+		for _, u := range strings.Split(value, ",") {
+			if u = strings.TrimSpace(u); u != "" {
+				fc.FallbackBaseURLs = append(fc.FallbackBaseURLs, u)
+			}
+		}
+		return nil
+	}},
+	intSetting("timeout-ms", "request timeout in milliseconds", func(fc *fileConfig) *int { return &fc.TimeoutMs }),
+	intSetting("max-retries", "retries per request", func(fc *fileConfig) *int { return &fc.MaxRetries }),
+	intSetting("retry-backoff-ms", "base retry backoff in milliseconds", func(fc *fileConfig) *int { return &fc.RetryBackoffMs }),
+	intSetting("retry-jitter-ms", "maximum retry jitter in milliseconds", func(fc *fileConfig) *int { return &fc.RetryJitterMs }),
+	intSetting("retry-min-backoff-ms", "shortest delay before any retry in milliseconds", func(fc *fileConfig) *int { return &fc.RetryMinBackoffMs }),
+	stringSetting("user-agent", "User-Agent header", func(fc *fileConfig) *string { return &fc.UserAgent }),
+}
+
+// envName maps a setting name to its environment variable, e.g. "base-url"
+// to CRONBEATS_BASE_URL.
+func envName(name string) string {
+	return "CRONBEATS_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// ConfigFlags are the flags registered by RegisterFlags.
+type ConfigFlags struct {
+	fs     *flag.FlagSet
+	config *string
+	values map[string]*string
+}
+
+// RegisterFlags registers -cronbeats-job-key, -cronbeats-base-url,
+// -cronbeats-timeout-ms and the other file settings on fs, plus
+// -cronbeats-config naming a config file as read by NewPingClientFromFile.
+// After fs is parsed, Load resolves them.
+func RegisterFlags(fs *flag.FlagSet) *ConfigFlags {
+	f := &ConfigFlags{fs: fs, values: map[string]*string{}}
+	f.config = fs.String("cronbeats-config", "", "path to a CronBeats JSON config file (env "+envName("config")+")")
+	for _, s := range settings {
+		f.values[s.name] = fs.String("cronbeats-"+s.name, "", s.usage+" (env "+envName(s.name)+")")
+	}
+	return f
+}
+
+// Load returns the job key and options. Each setting is taken from the
+// first source that has it: a flag set on the command line, the
+// CRONBEATS_* environment variable, the config file, then the Options
+// default. The config file itself comes from -cronbeats-config or
+// CRONBEATS_CONFIG and may omit the job key.
+func (f *ConfigFlags) Load() (string, Options, error) {
+	explicit := map[string]bool{}
+	f.fs.Visit(func(fl *flag.Flag) { explicit[fl.Name] = true })
+
+	var fc fileConfig
+	path := *f.config
+	if !explicit["cronbeats-config"] {
+		path = os.Getenv(envName("config"))
+	}
+	if path != "" {
+		var err error
+		if fc, err = readConfigFile(path); err != nil {
+			return "", Options{}, err
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := os.LookupEnv(envName(s.name)); ok {
+			if err := s.set(&fc, value); err != nil {
+				return "", Options{}, &ValidationError{Message: fmt.Sprintf("%s %s", envName(s.name), err)}
+			}
+		}
+		if explicit["cronbeats-"+s.name] {
+			if err := s.set(&fc, *f.values[s.name]); err != nil {
+				return "", Options{}, &ValidationError{Message: fmt.Sprintf("flag -cronbeats-%s %s", s.name, err)}
+			}
+		}
+	}
+
+	if !jobKeyRegex.MatchString(fc.JobKey) {
+		return "", Options{}, &ValidationError{Message: "job key must be exactly 8 Base62 characters; set -cronbeats-job-key or " + envName("job-key")}
+	}
+	return fc.JobKey, fc.options(), nil
+}
+
+// NewPingClient builds a client from Load.
+func (f *ConfigFlags) NewPingClient() (*PingClient, error) {
+	jobKey, options, err := f.Load()
+	if err != nil {
+		return nil, err
+	}
+	return NewPingClient(jobKey, &options)
+}
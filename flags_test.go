@@ -0,0 +1,66 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRegisterFlagsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cronbeats.json")
+	if err := os.WriteFile(path, []byte(`{"job_key":"filekey1","base_url":"https://file.example.com","timeout_ms":1000,"max_retries":5,"user_agent":"file-agent"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CRONBEATS_CONFIG", path)
+	t.Setenv("CRONBEATS_TIMEOUT_MS", "2000")
+	t.Setenv("CRONBEATS_BASE_URL", "https://env.example.com")
+	t.Setenv("CRONBEATS_FALLBACK_BASE_URLS", "https://a.example.com, https://b.example.com")
+
+	fs := flag.NewFlagSet("job", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"-cronbeats-timeout-ms", "3000", "-cronbeats-job-key", "flagkey1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	jobKey, opts, err := flags.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if jobKey != "flagkey1" || opts.TimeoutMs != 3000 || opts.BaseURL != "https://env.example.com" || opts.MaxRetries != 5 || opts.UserAgent != "file-agent" {
+		t.Fatalf("unexpected resolution: %s %+v", jobKey, opts)
+	}
+	if !reflect.DeepEqual(opts.FallbackBaseURLs, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Fatalf("unexpected fallbacks: %v", opts.FallbackBaseURLs)
+	}
+	if opts.RetryBackoffMs != 0 {
+		t.Fatalf("expected unset values to keep the default, got %d", opts.RetryBackoffMs)
+	}
+}
+
+func TestRegisterFlagsErrors(t *testing.T) {
+	fs := flag.NewFlagSet("job", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse([]string{"-cronbeats-job-key", "abc123de", "-cronbeats-max-retries", "many"}); err != nil {
+		t.Fatal(err)
+	}
+	var vErr *ValidationError
+	if _, err := flags.NewPingClient(); !errors.As(err, &vErr) || vErr.Message != `flag -cronbeats-max-retries must be an integer, got "many"` {
+		t.Fatalf("expected flag ValidationError, got %v", err)
+	}
+
+	fs = flag.NewFlagSet("job", flag.ContinueOnError)
+	flags = RegisterFlags(fs)
+	_ = fs.Parse(nil)
+	if _, _, err := flags.Load(); !errors.As(err, &vErr) {
+		t.Fatalf("expected missing job key error, got %v", err)
+	}
+
+	t.Setenv("CRONBEATS_JOB_KEY", "envkey12")
+	client, err := flags.NewPingClient()
+	if err != nil || client.jobKey != "envkey12" {
+		t.Fatalf("expected client from env, got %v", err)
+	}
+}
@@ -0,0 +1,113 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultHeartbeatInterval = time.Minute
+
+// heartbeatErrorBuffer is how many heartbeat errors are kept for a caller
+// that is not reading them; further errors are dropped until it catches up.
+const heartbeatErrorBuffer = 16
+
+type AdaptiveHeartbeatOptions struct {
+	// FallbackInterval is used when a response carries no next_expected.
+	FallbackInterval time.Duration
+	// Lead is how long before next_expected the next ping is sent.
+	Lead time.Duration
+	// Jitter pulls each scheduled ping earlier by a random amount up to
+	// this value, so a fleet of workers does not ping in lockstep.
+	Jitter time.Duration
+	// MinInterval bounds how often pings are sent, however close
+	// next_expected is.
+	MinInterval time.Duration
+}
+
+// StartHeartbeat pings immediately and then every interval until stop is
+// called.
+//
+// Each failed ping is sent on errs, which is closed when the heartbeat ends.
+// It buffers a few errors and drops further ones while full, so callers
+// should drain it or ignore it entirely.
+//
+// stop ends the heartbeat and waits for a ping in flight to complete, or
+// until ctx is done, in which case it returns ctx.Err(). It may be called
+// more than once.
+func (c *PingClient) StartHeartbeat(interval time.Duration) (stop func(ctx context.Context) error, errs <-chan error) {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	return c.runHeartbeat(func(*PingSuccess) time.Duration { return interval })
+}
+
+// StartAdaptiveHeartbeat pings immediately and schedules each following ping
+// just ahead of the next_expected time returned by the previous one. stop and
+// errs behave as for StartHeartbeat.
+func (c *PingClient) StartAdaptiveHeartbeat(opts AdaptiveHeartbeatOptions) (stop func(ctx context.Context) error, errs <-chan error) {
+	return c.runHeartbeat(c.adaptiveDelay(opts))
+}
+
+func (c *PingClient) adaptiveDelay(opts AdaptiveHeartbeatOptions) func(*PingSuccess) time.Duration {
+	fallback := opts.FallbackInterval
+	if fallback <= 0 {
+		fallback = defaultHeartbeatInterval
+	}
+	minInterval := opts.MinInterval
+	if minInterval <= 0 {
+		minInterval = time.Second
+	}
+
+	return func(res *PingSuccess) time.Duration {
+		delay := fallback
+		if res != nil && res.NextExpected != nil {
+			if next, err := parseTimestamp(*res.NextExpected); err == nil {
+				delay = next.Sub(c.now()) - opts.Lead
+			}
+		}
+		if opts.Jitter > 0 {
+			delay -= time.Duration(c.randIntn(int(opts.Jitter) + 1))
+		}
+		if delay < minInterval {
+			delay = minInterval
+		}
+		return delay
+	}
+}
+
+func (c *PingClient) runHeartbeat(nextDelay func(*PingSuccess) time.Duration) (stop func(ctx context.Context) error, errs <-chan error) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	errCh := make(chan error, heartbeatErrorBuffer)
+	go func() {
+		defer close(exited)
+		defer close(errCh)
+		for {
+			res, err := c.Ping()
+			if err != nil {
+				select {
+				case errCh <- err:
+				default:
+				}
+			}
+			select {
+			case <-done:
+				return
+			case <-c.after(nextDelay(res)):
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop = func(ctx context.Context) error {
+		once.Do(func() { close(done) })
+		select {
+		case <-exited:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return stop, errCh
+}
@@ -0,0 +1,146 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type syncStubClient struct {
+	mu       sync.Mutex
+	calls    int
+	response func(call int) *HttpResponse
+}
+
+func (s *syncStubClient) Request(_ string, _ string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	s.mu.Lock()
+	s.calls++
+	call := s.calls
+	s.mu.Unlock()
+	if s.response != nil {
+		return s.response(call), nil
+	}
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func (s *syncStubClient) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func TestAdaptiveHeartbeatSchedulesBeforeNextExpected(t *testing.T) {
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client := newTestClient(t, &syncStubClient{}, nil)
+	client.now = func() time.Time { return now }
+
+	opts := AdaptiveHeartbeatOptions{FallbackInterval: 30 * time.Second, Lead: 5 * time.Second, MinInterval: time.Second}
+	delay := client.adaptiveDelay(opts)
+
+	next := "2026-02-25 12:01:00"
+	cases := []struct {
+		res  *PingSuccess
+		want time.Duration
+	}{
+		{res: &PingSuccess{NextExpected: &next}, want: 55 * time.Second},
+		{res: &PingSuccess{}, want: 30 * time.Second},
+		{res: nil, want: 30 * time.Second},
+	}
+	for _, tc := range cases {
+		if got := delay(tc.res); got != tc.want {
+			t.Fatalf("expected delay %v, got %v", tc.want, got)
+		}
+	}
+
+	past := "2026-02-25 11:59:00"
+	if got := delay(&PingSuccess{NextExpected: &past}); got != time.Second {
+		t.Fatalf("expected delay clamped to MinInterval, got %v", got)
+	}
+}
+
+func TestHeartbeatPingsUntilStopped(t *testing.T) {
+	http := &syncStubClient{}
+	client := newTestClient(t, http, nil)
+
+	stop, _ := client.StartHeartbeat(5 * time.Millisecond)
+	deadline := time.Now().Add(time.Second)
+	for http.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error on second stop: %v", err)
+	}
+	if http.count() < 3 {
+		t.Fatalf("expected at least 3 heartbeats, got %d", http.count())
+	}
+}
+
+func TestHeartbeatStopWaitsForInFlightPing(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var landed atomic.Bool
+	http := &syncStubClient{response: func(call int) *HttpResponse {
+		if call == 1 {
+			close(started)
+			<-release
+			landed.Store(true)
+		}
+		return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}
+	}}
+	client := newTestClient(t, http, nil)
+
+	stop, _ := client.StartHeartbeat(time.Hour)
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected stop to time out while the ping is blocked, got %v", err)
+	}
+
+	close(release)
+	if err := stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !landed.Load() {
+		t.Fatalf("stop returned before the in-flight ping completed")
+	}
+	if n := http.count(); n != 1 {
+		t.Fatalf("expected no pings after stop, got %d", n)
+	}
+}
+
+func TestHeartbeatSurfacesPingErrors(t *testing.T) {
+	http := &syncStubClient{response: func(call int) *HttpResponse {
+		if call <= 2 {
+			return &HttpResponse{Status: 404, Body: `{"message":"Job not found"}`, Headers: map[string]string{}}
+		}
+		return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}
+	}}
+	client := newTestClient(t, http, nil)
+
+	stop, errs := client.StartHeartbeat(time.Millisecond)
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			var apiErr *ApiError
+			if !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound {
+				t.Fatalf("unexpected heartbeat error: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected heartbeat error %d", i+1)
+		}
+	}
+	if err := stop(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for err := range errs {
+		t.Fatalf("unexpected error after recovery: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// HistoryEntry is one ping record of the job, newest first. Fields the
+// server did not send are left at their zero value.
+type HistoryEntry struct {
+	Action     string
+	Status     string
+	Message    string
+	Timestamp  *time.Time
+	DurationMs float64
+	Raw        map[string]any
+}
+
+// maxHistoryPrealloc caps the capacity History reserves up front, so a large
+// limit does not allocate for entries the job may not have.
+const maxHistoryPrealloc = 100
+
+// History fetches up to limit of the job's most recent ping records,
+// following the server's next_cursor across pages. ctx is checked before
+// each page, so a cancellation stops the walk and returns its error.
+func (c *PingClient) History(ctx context.Context, limit int) ([]HistoryEntry, error) {
+	if limit <= 0 {
+		return nil, &ValidationError{Message: "History limit must be positive."}
+	}
+	cfg := c.config()
+	headers := map[string]string{"Accept": cfg.accept, "User-Agent": cfg.userAgent}
+	base := cfg.buildURL(cfg.endpoints[0], c.jobKey, "history", nil)
+
+	entries := make([]HistoryEntry, 0, min(limit, maxHistoryPrealloc))
+	cursor := ""
+	for len(entries) < limit {
+		if err := ctx.Err(); err != nil {
+			return entries, &SdkError{Message: "history request canceled", Cause: err}
+		}
+		query := url.Values{"limit": {strconv.Itoa(limit - len(entries))}}
+		if cursor != "" {
+			query.Set("cursor", cursor)
+		}
+		var res *HttpResponse
+		var err error
+		if cfg.dryRun {
+			res = c.recordDryRun(&OutgoingRequest{Method: "GET", URL: base + "?" + query.Encode(), Headers: headers})
+		} else {
+			res, err = sendRequest(withRequestInfo(ctx, c.jobKey, "history"), cfg.httpClient, "GET", base+"?"+query.Encode(), headers, nil, cfg.timeoutMs)
+		}
+		if err != nil {
+			return entries, &ApiError{Code: CodeNetwork, Retryable: true, Message: err.Error(), Raw: err}
+		}
+		parsed := cfg.decodeBody(res.Body)
+		if ok, code, retryable := cfg.classify(res, parsed); !ok {
+			msg := cfg.errorMessageOf(parsed)
+			if msg == "" {
+				msg = "Request failed"
+			}
+			status := res.Status
+			return entries, &ApiError{Code: code, HTTPStatus: &status, Retryable: retryable, Message: msg, Raw: parsed}
+		}
+
+		page, _ := parsed["entries"].([]any)
+		for _, item := range page {
+			raw, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+			entries = append(entries, parseHistoryEntry(raw))
+			if len(entries) == limit {
+				break
+			}
+		}
+		cursor, _ = parsed["next_cursor"].(string)
+		if cursor == "" || len(page) == 0 {
+			break
+		}
+	}
+	return entries, nil
+}
+
+func parseHistoryEntry(raw map[string]any) HistoryEntry {
+	entry := HistoryEntry{Raw: raw, DurationMs: floatOrZero(raw["duration_ms"])}
+	entry.Action, _ = raw["action"].(string)
+	entry.Status, _ = raw["status"].(string)
+	entry.Message, _ = raw["message"].(string)
+	entry.Timestamp = rawTime(raw, "timestamp", "created_at")
+	return entry
+}
@@ -0,0 +1,64 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistoryFollowsCursors(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{
+		{status: 200, body: `{"entries":[{"action":"end","status":"success","timestamp":"2026-02-25 12:00:00","duration_ms":1500},{"action":"start"}],"next_cursor":"c2"}`},
+		{status: 200, body: `{"entries":[{"action":"ping"},{"action":"ping"}],"next_cursor":"c3"}`},
+	}}
+	client := newTestClient(t, http, nil)
+
+	entries, err := client.History(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 || entries[0].Action != "end" || entries[0].Status != "success" || entries[0].DurationMs != 1500 || entries[2].Action != "ping" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+	if entries[0].Timestamp == nil || !entries[0].Timestamp.Equal(time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected timestamp: %v", entries[0].Timestamp)
+	}
+	if len(http.calls) != 2 || http.calls[0].method != "GET" {
+		t.Fatalf("expected two GET pages, got %+v", http.calls)
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/history?limit=3" || http.calls[1].url != "https://cronbeats.io/ping/abc123de/history?cursor=c2&limit=1" {
+		t.Fatalf("unexpected page urls: %s, %s", http.calls[0].url, http.calls[1].url)
+	}
+}
+
+func TestHistoryStopsWithoutCursor(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{"entries":[{"action":"ping"}]}`}}}
+	client := newTestClient(t, http, nil)
+
+	entries, err := client.History(context.Background(), 10)
+	if err != nil || len(entries) != 1 || len(http.calls) != 1 {
+		t.Fatalf("unexpected result: %+v, %v, %d calls", entries, err, len(http.calls))
+	}
+}
+
+func TestHistoryErrors(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 404, body: `{"message":"no history"}`}}}
+	client := newTestClient(t, http, nil)
+
+	var apiErr *ApiError
+	if _, err := client.History(context.Background(), 5); !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound || apiErr.Message != "no history" {
+		t.Fatalf("expected not found ApiError, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.History(ctx, 5); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.History(context.Background(), 0); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
@@ -2,9 +2,12 @@ package cronbeatsgo
 
 import (
 	"bytes"
+	"context"
 	"io"
+	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -14,14 +17,90 @@ type HttpResponse struct {
 	Headers map[string]string
 }
 
+// OutgoingRequest is one attempt as it will be handed to the HttpClient.
+type OutgoingRequest struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// ExchangeInfo is one HTTP attempt exactly as it was sent, for
+// Options.OnExchange. Attempt is 1-based. Headers and Body are copies and
+// are not redacted.
+type ExchangeInfo struct {
+	Action  string
+	Attempt int
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+	Latency time.Duration
+}
+
+// RequestInterceptor may modify req before every attempt, e.g. to sign it.
+// attempt is 0 for the first try; on retries prevErr is the error of the
+// previous attempt. A returned error aborts the request without retrying.
+type RequestInterceptor func(req *OutgoingRequest, attempt int, prevErr error) error
+
 type HttpClient interface {
 	Request(method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error)
 }
 
-type NetHTTPClient struct{}
+// ContextHttpClient is implemented by transports that accept a context. The
+// client prefers it over Request; the context carries the job key and action
+// (see JobKeyFromContext and ActionFromContext).
+type ContextHttpClient interface {
+	HttpClient
+	RequestContext(ctx context.Context, method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error)
+}
+
+// NetHTTPClient sends requests with net/http. The timeoutMs passed to each
+// request bounds the whole exchange; ConnectTimeoutMs and
+// ResponseHeaderTimeoutMs additionally bound its phases, so an unreachable
+// endpoint can fail fast while a slow but live one is given the full
+// timeout. Zero leaves a phase bounded only by timeoutMs.
+type NetHTTPClient struct {
+	// ConnectTimeoutMs limits establishing the TCP connection.
+	ConnectTimeoutMs int
+	// ResponseHeaderTimeoutMs limits waiting for the response headers once
+	// the request has been written.
+	ResponseHeaderTimeoutMs int
+	// FollowRedirects lets net/http follow up to 10 redirects. By default a
+	// redirect is returned as is and reported as CodeRedirect, since the
+	// ping API never redirects and following one could send pings to an
+	// unexpected host.
+	FollowRedirects bool
+
+	transportOnce sync.Once
+	transport     http.RoundTripper
+}
+
+func (c *NetHTTPClient) roundTripper() http.RoundTripper {
+	c.transportOnce.Do(func() {
+		if c.ConnectTimeoutMs <= 0 && c.ResponseHeaderTimeoutMs <= 0 {
+			c.transport = http.DefaultTransport
+			return
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if c.ConnectTimeoutMs > 0 {
+			dialer := &net.Dialer{Timeout: time.Duration(c.ConnectTimeoutMs) * time.Millisecond, KeepAlive: 30 * time.Second}
+			transport.DialContext = dialer.DialContext
+		}
+		if c.ResponseHeaderTimeoutMs > 0 {
+			transport.ResponseHeaderTimeout = time.Duration(c.ResponseHeaderTimeoutMs) * time.Millisecond
+		}
+		c.transport = transport
+	})
+	return c.transport
+}
 
 func (c *NetHTTPClient) Request(method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error) {
-	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	return c.RequestContext(context.Background(), method, url, headers, body, timeoutMs)
+}
+
+func (c *NetHTTPClient) RequestContext(ctx context.Context, method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, &SdkError{Message: "failed to create request", Cause: err}
 	}
@@ -30,7 +109,10 @@ func (c *NetHTTPClient) Request(method string, url string, headers map[string]st
 		req.Header.Set(key, value)
 	}
 
-	client := &http.Client{Timeout: time.Duration(timeoutMs) * time.Millisecond}
+	client := &http.Client{Timeout: time.Duration(timeoutMs) * time.Millisecond, Transport: c.roundTripper()}
+	if !c.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	}
 	res, err := client.Do(req)
 	if err != nil {
 		return nil, &SdkError{Message: "network request failed", Cause: err}
@@ -53,3 +135,10 @@ func (c *NetHTTPClient) Request(method string, url string, headers map[string]st
 		Headers: outHeaders,
 	}, nil
 }
+
+func sendRequest(ctx context.Context, client HttpClient, method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error) {
+	if cc, ok := client.(ContextHttpClient); ok {
+		return cc.RequestContext(ctx, method, url, headers, body, timeoutMs)
+	}
+	return client.Request(method, url, headers, body, timeoutMs)
+}
@@ -0,0 +1,102 @@
+package cronbeatsgo
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+const (
+	LogEventRequest = "request"
+	LogEventRetry   = "retry"
+	LogEventSuccess = "success"
+	LogEventError   = "error"
+	LogEventWarning = "warning"
+)
+
+// LogEvent describes one step of a ping. Attempt is 1-based; Status is 0
+// when no HTTP response was received. Body is the request body on request
+// events and the response body otherwise, after Options.Redactor. Fields
+// carries the caller's CallOptions.LogFields, if any.
+type LogEvent struct {
+	// Time is when the event occurred, according to Options.Clock.
+	Time    time.Time
+	Type    string
+	JobKey  string
+	Action  string
+	Status  int
+	Attempt int
+	Latency time.Duration
+	Err     error
+	Body    []byte
+	Fields  map[string]any
+}
+
+type Logger interface {
+	Log(event LogEvent)
+}
+
+type jsonLogger struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLogger returns a Logger that writes each event to w as a single
+// JSON object per line.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{enc: json.NewEncoder(w)}
+}
+
+func (l *jsonLogger) Log(event LogEvent) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	line := map[string]any{
+		"time":    event.Time.UTC().Format(time.RFC3339Nano),
+		"event":   event.Type,
+		"job_key": event.JobKey,
+		"action":  event.Action,
+		"attempt": event.Attempt,
+	}
+	if event.Status != 0 {
+		line["status"] = event.Status
+	}
+	if event.Latency > 0 {
+		line["latency_ms"] = float64(event.Latency) / float64(time.Millisecond)
+	}
+	if event.Err != nil {
+		line["error"] = event.Err.Error()
+	}
+	if len(event.Body) > 0 {
+		line["body"] = string(event.Body)
+	}
+	for key, value := range event.Fields {
+		if _, taken := line[key]; !taken {
+			line[key] = value
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.enc.Encode(line)
+}
+
+func (o *Options) WithJSONLogger(w io.Writer) *Options {
+	o.Logger = NewJSONLogger(w)
+	return o
+}
+
+func (cfg *clientConfig) redact(body []byte) []byte {
+	if cfg.redactor == nil || len(body) == 0 {
+		return body
+	}
+	return cfg.redactor(append([]byte(nil), body...))
+}
+
+func (c *PingClient) log(cfg *clientConfig, event LogEvent) {
+	if cfg.logger != nil {
+		event.Time = c.now()
+		cfg.logger.Log(event)
+	}
+}
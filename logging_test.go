@@ -0,0 +1,75 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestJSONLoggerWritesOneLinePerEvent(t *testing.T) {
+	var out bytes.Buffer
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 503, body: `{"message":"busy"}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, (&Options{MaxRetries: 1}).WithJSONLogger(&out))
+
+	if _, err := client.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	wantEvents := []string{"request", "retry", "request", "success"}
+	if len(lines) != len(wantEvents) {
+		t.Fatalf("expected %d log lines, got %d:\n%s", len(wantEvents), len(lines), out.String())
+	}
+	for i, line := range lines {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if entry["event"] != wantEvents[i] || entry["job_key"] != "abc123de" || entry["action"] != "start" {
+			t.Fatalf("unexpected entry %d: %v", i, entry)
+		}
+	}
+
+	var retry map[string]any
+	_ = json.Unmarshal([]byte(lines[1]), &retry)
+	if retry["status"] != 503.0 || retry["attempt"] != 1.0 || retry["error"] != "busy" {
+		t.Fatalf("unexpected retry entry: %v", retry)
+	}
+}
+
+func TestRedactorAppliesToLoggedBodies(t *testing.T) {
+	var out bytes.Buffer
+	http := &stubHTTPClient{
+		responses: []stubResponse{
+			{status: 400, body: `{"message":"bad request","echo":"token=s3cret"}`},
+		},
+	}
+	opts := (&Options{
+		Redactor: func(body []byte) []byte {
+			return bytes.ReplaceAll(body, []byte("s3cret"), []byte("***"))
+		},
+	}).WithJSONLogger(&out)
+	client := newTestClient(t, http, opts)
+
+	_, err := client.Progress(nil, "using token=s3cret")
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected ApiError, got %v", err)
+	}
+	if strings.Contains(apiErr.BodyPreview, "s3cret") {
+		t.Fatalf("body preview was not redacted: %s", apiErr.BodyPreview)
+	}
+	if strings.Contains(out.String(), "s3cret") || !strings.Contains(out.String(), "token=***") {
+		t.Fatalf("log output was not redacted:\n%s", out.String())
+	}
+	if !strings.Contains(http.calls[0].body, "s3cret") {
+		t.Fatalf("redaction must not change the request sent: %s", http.calls[0].body)
+	}
+}
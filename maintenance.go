@@ -0,0 +1,51 @@
+package cronbeatsgo
+
+import (
+	"sync"
+	"time"
+)
+
+// maintenanceWindow is shared by every client in the process, since a
+// server in maintenance is down for all of its jobs.
+var maintenanceWindow struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// maintenanceNow times the maintenance window. It is the real clock rather
+// than a client's Clock, as the window is shared by clients that may each
+// use a different one.
+var maintenanceNow = time.Now
+
+func maintenanceUntil() (time.Time, bool) {
+	maintenanceWindow.mu.Lock()
+	defer maintenanceWindow.mu.Unlock()
+	return maintenanceWindow.until, maintenanceNow().Before(maintenanceWindow.until)
+}
+
+func extendMaintenance(until time.Time) {
+	maintenanceWindow.mu.Lock()
+	defer maintenanceWindow.mu.Unlock()
+	if until.After(maintenanceWindow.until) {
+		maintenanceWindow.until = until
+	}
+}
+
+func isMaintenance(res *HttpResponse, parsed map[string]any) bool {
+	flag, _ := parsed["maintenance"].(bool)
+	return flag && res.Status == 503
+}
+
+// handleMaintenance opens the maintenance window for a maintenance response
+// and reports whether the error may still be retried.
+func (c *PingClient) handleMaintenance(cfg *clientConfig) bool {
+	var until time.Time
+	if cfg.maintenance > 0 {
+		until = maintenanceNow().Add(cfg.maintenance)
+		extendMaintenance(until)
+	}
+	if cfg.onMaintenance != nil {
+		cfg.onMaintenance(until)
+	}
+	return cfg.maintenance == 0
+}
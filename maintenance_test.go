@@ -0,0 +1,98 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func resetMaintenanceWindow(t *testing.T) {
+	t.Helper()
+	maintenanceWindow.until = time.Time{}
+	t.Cleanup(func() {
+		maintenanceWindow.until = time.Time{}
+		maintenanceNow = time.Now
+	})
+}
+
+func TestMaintenanceResponseOpensProcessWideWindow(t *testing.T) {
+	resetMaintenanceWindow(t)
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+
+	var windows []time.Time
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{"maintenance":true,"message":"Scheduled maintenance"}`}}}
+	client := newTestClient(t, http, &Options{
+		MaxRetries:         3,
+		MaintenanceBackoff: 10 * time.Minute,
+		OnMaintenance:      func(until time.Time) { windows = append(windows, until) },
+	})
+	maintenanceNow = func() time.Time { return now }
+
+	_, err := client.Ping()
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeMaintenance || apiErr.Retryable {
+		t.Fatalf("expected non-retryable maintenance error, got %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected no retries during maintenance, got %d calls", len(http.calls))
+	}
+	if len(windows) != 1 || !windows[0].Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("unexpected OnMaintenance calls: %v", windows)
+	}
+
+	otherHTTP := &stubHTTPClient{}
+	other := newTestClient(t, otherHTTP, nil)
+	maintenanceNow = func() time.Time { return now.Add(5 * time.Minute) }
+	if _, err := other.Start(); !errors.As(err, &apiErr) || apiErr.Code != CodeMaintenance {
+		t.Fatalf("expected request inside the window to be skipped, got %v", err)
+	}
+	if len(otherHTTP.calls) != 0 {
+		t.Fatalf("expected nothing sent during the window, got %d calls", len(otherHTTP.calls))
+	}
+
+	maintenanceNow = func() time.Time { return now.Add(11 * time.Minute) }
+	if _, err := other.Start(); err != nil {
+		t.Fatalf("expected requests to resume after the window, got %v", err)
+	}
+}
+
+func TestMaintenanceWithoutBackoffRetriesNormally(t *testing.T) {
+	resetMaintenanceWindow(t)
+
+	calls := 0
+	http := &stubHTTPClient{responses: []stubResponse{
+		{status: 503, body: `{"maintenance":true}`},
+		{status: 200, body: `{}`},
+	}}
+	client := newTestClient(t, http, &Options{MaxRetries: 1, OnMaintenance: func(until time.Time) {
+		calls++
+		if !until.IsZero() {
+			t.Errorf("expected zero window without MaintenanceBackoff, got %v", until)
+		}
+	}})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 1 || len(http.calls) != 2 {
+		t.Fatalf("expected one maintenance callback and a retry, got %d callbacks and %d calls", calls, len(http.calls))
+	}
+}
+
+func TestMaintenanceWindowIgnoresClientClocks(t *testing.T) {
+	resetMaintenanceWindow(t)
+
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{"maintenance":true}`}}}
+	fake := newTestClient(t, http, &Options{MaintenanceBackoff: time.Minute})
+	fake.now = func() time.Time { return time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC) }
+	_, _ = fake.Ping()
+
+	if until, active := maintenanceUntil(); !active || until.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("expected a one minute window on the real clock, got %v", until)
+	}
+	maintenanceNow = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	otherHTTP := &stubHTTPClient{}
+	if _, err := newTestClient(t, otherHTTP, nil).Ping(); err != nil || len(otherHTTP.calls) != 1 {
+		t.Fatalf("expected requests to resume once the window passed, got %v", err)
+	}
+}
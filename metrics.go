@@ -0,0 +1,138 @@
+package cronbeatsgo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AttemptMetric describes one HTTP attempt. Status is 0 and Code is
+// CodeNetwork when no response was received; Code is empty on success.
+// Labels holds the caller's CallOptions.Labels, if any.
+type AttemptMetric struct {
+	Action  string
+	Status  int
+	Code    ApiErrorCode
+	Attempt int
+	Latency time.Duration
+	Labels  map[string]string
+}
+
+// MetricsRecorder receives a measurement for every HTTP attempt, including
+// retries. Implementations typically count attempts by Action and Status and
+// record Latency in a histogram.
+type MetricsRecorder interface {
+	RecordAttempt(m AttemptMetric)
+}
+
+func (cfg *clientConfig) recordAttempt(m AttemptMetric) {
+	if cfg.metrics != nil {
+		cfg.metrics.RecordAttempt(m)
+	}
+}
+
+// MetricsSnapshot holds the client's cumulative counters. Requests counts
+// completed calls, each of which is either a success or a failure; Attempts
+// and Retries count the HTTP attempts behind them.
+type MetricsSnapshot struct {
+	Requests       int64
+	Successes      int64
+	Failures       int64
+	FailuresByCode map[ApiErrorCode]int64
+	Attempts       int64
+	Retries        int64
+}
+
+type clientCounters struct {
+	mu     sync.Mutex
+	totals MetricsSnapshot
+}
+
+func (m *clientCounters) attempt(retry bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totals.Attempts++
+	if retry {
+		m.totals.Retries++
+	}
+}
+
+func (m *clientCounters) outcome(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.totals.Requests++
+	if err == nil {
+		m.totals.Successes++
+		return
+	}
+	m.totals.Failures++
+	code := CodeUnknown
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) {
+		code = apiErr.Code
+	}
+	if m.totals.FailuresByCode == nil {
+		m.totals.FailuresByCode = map[ApiErrorCode]int64{}
+	}
+	m.totals.FailuresByCode[code]++
+}
+
+// Metrics returns a consistent copy of the client's counters since it was
+// created.
+func (c *PingClient) Metrics() MetricsSnapshot {
+	c.counters.mu.Lock()
+	defer c.counters.mu.Unlock()
+	snapshot := c.counters.totals
+	snapshot.FailuresByCode = make(map[ApiErrorCode]int64, len(c.counters.totals.FailuresByCode))
+	for code, n := range c.counters.totals.FailuresByCode {
+		snapshot.FailuresByCode[code] = n
+	}
+	return snapshot
+}
+
+// WriteOpenMetrics writes the Metrics snapshot and DroppedPings to w in the
+// OpenMetrics text format, which Prometheus also scrapes, for serving from
+// a /metrics handler without a metrics library. Every sample carries a
+// job_key label; failures are broken down by a code label. The output ends
+// with "# EOF".
+func (c *PingClient) WriteOpenMetrics(w io.Writer) error {
+	snapshot := c.Metrics()
+	job := `job_key="` + escapeLabel(c.jobKey) + `"`
+	out := bufio.NewWriter(w)
+	counter := func(name string, help string) {
+		fmt.Fprintf(out, "# TYPE %s counter\n# HELP %s %s\n", name, name, help)
+	}
+
+	counter("cronbeats_requests", "Completed requests.")
+	fmt.Fprintf(out, "cronbeats_requests_total{%s} %d\n", job, snapshot.Requests)
+	counter("cronbeats_successes", "Requests that succeeded.")
+	fmt.Fprintf(out, "cronbeats_successes_total{%s} %d\n", job, snapshot.Successes)
+	counter("cronbeats_failures", "Requests that failed, by error code.")
+	codes := make([]string, 0, len(snapshot.FailuresByCode))
+	for code := range snapshot.FailuresByCode {
+		codes = append(codes, string(code))
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		fmt.Fprintf(out, "cronbeats_failures_total{%s,code=\"%s\"} %d\n", job, escapeLabel(code), snapshot.FailuresByCode[ApiErrorCode(code)])
+	}
+	counter("cronbeats_attempts", "HTTP attempts, including retries.")
+	fmt.Fprintf(out, "cronbeats_attempts_total{%s} %d\n", job, snapshot.Attempts)
+	counter("cronbeats_retries", "HTTP attempts that were retries.")
+	fmt.Fprintf(out, "cronbeats_retries_total{%s} %d\n", job, snapshot.Retries)
+	counter("cronbeats_dropped", "Pings dropped by the rate limiter.")
+	fmt.Fprintf(out, "cronbeats_dropped_total{%s} %d\n", job, c.DroppedPings())
+	out.WriteString("# EOF\n")
+	return out.Flush()
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,143 @@
+package cronbeatsgo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type recordingMetrics struct {
+	attempts []AttemptMetric
+}
+
+func (r *recordingMetrics) RecordAttempt(m AttemptMetric) {
+	r.attempts = append(r.attempts, m)
+}
+
+func TestMetricsRecorderSeesEveryAttempt(t *testing.T) {
+	http := &stubHTTPClient{
+		networkFailures: 1,
+		responses: []stubResponse{
+			{status: 503, body: `{}`},
+			{status: 200, body: `{}`},
+		},
+	}
+	metrics := &recordingMetrics{}
+	client := newTestClient(t, http, &Options{MaxRetries: 2, Metrics: metrics})
+
+	if _, err := client.Progress(nil, "step"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []AttemptMetric{
+		{Action: "progress", Status: 0, Code: CodeNetwork, Attempt: 1},
+		{Action: "progress", Status: 503, Code: CodeServer, Attempt: 2},
+		{Action: "progress", Status: 200, Attempt: 3},
+	}
+	if len(metrics.attempts) != len(want) {
+		t.Fatalf("expected %d attempts, got %#v", len(want), metrics.attempts)
+	}
+	for i, got := range metrics.attempts {
+		got.Latency = 0
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Fatalf("attempt %d: got %#v, want %#v", i, got, want[i])
+		}
+	}
+}
+
+func TestCallLabelsFlowIntoMetrics(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 200, body: `{}`}}}
+	metrics := &recordingMetrics{}
+	client := newTestClient(t, http, &Options{MaxRetries: 1, Metrics: metrics})
+
+	staging := client.With(CallOptions{Labels: map[string]string{"env": "staging", "stage": "extract"}})
+	if _, err := staging.With(CallOptions{Labels: map[string]string{"stage": "load"}}).Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]string{"env": "staging", "stage": "load"}
+	if len(metrics.attempts) != 2 || !reflect.DeepEqual(metrics.attempts[0].Labels, want) || !reflect.DeepEqual(metrics.attempts[1].Labels, want) {
+		t.Fatalf("unexpected attempts: %#v", metrics.attempts)
+	}
+
+	metrics.attempts = nil
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if metrics.attempts[0].Labels != nil {
+		t.Fatalf("expected no labels on the parent client, got %v", metrics.attempts[0].Labels)
+	}
+}
+
+func TestMetricsSnapshotCountsOutcomes(t *testing.T) {
+	http := &stubHTTPClient{
+		networkFailures: 1,
+		responses: []stubResponse{
+			{status: 200, body: `{}`},
+			{status: 404, body: `{"message":"Job not found"}`},
+			{status: 503, body: `{}`},
+			{status: 503, body: `{}`},
+		},
+	}
+	client := newTestClient(t, http, &Options{MaxRetries: 1})
+
+	_, _ = client.Ping()
+	_, _ = client.Start()
+	_, _ = client.Success()
+
+	got := client.Metrics()
+	want := MetricsSnapshot{
+		Requests:       3,
+		Successes:      1,
+		Failures:       2,
+		FailuresByCode: map[ApiErrorCode]int64{CodeNotFound: 1, CodeServer: 1},
+		Attempts:       5,
+		Retries:        2,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected snapshot: %+v", got)
+	}
+
+	got.FailuresByCode[CodeServer] = 99
+	if client.Metrics().FailuresByCode[CodeServer] != 1 {
+		t.Fatalf("snapshot must be a copy")
+	}
+}
+
+func TestWriteOpenMetricsRendersCounters(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 404, body: `{}`}}}
+	client := newTestClient(t, http, &Options{MaxRetries: 1})
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+
+	var out strings.Builder
+	if err := client.WriteOpenMetrics(&out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# TYPE cronbeats_requests counter
+# HELP cronbeats_requests Completed requests.
+cronbeats_requests_total{job_key="abc123de"} 3
+# TYPE cronbeats_successes counter
+# HELP cronbeats_successes Requests that succeeded.
+cronbeats_successes_total{job_key="abc123de"} 2
+# TYPE cronbeats_failures counter
+# HELP cronbeats_failures Requests that failed, by error code.
+cronbeats_failures_total{job_key="abc123de",code="NOT_FOUND"} 1
+# TYPE cronbeats_attempts counter
+# HELP cronbeats_attempts HTTP attempts, including retries.
+cronbeats_attempts_total{job_key="abc123de"} 4
+# TYPE cronbeats_retries counter
+# HELP cronbeats_retries HTTP attempts that were retries.
+cronbeats_retries_total{job_key="abc123de"} 1
+# TYPE cronbeats_dropped counter
+# HELP cronbeats_dropped Pings dropped by the rate limiter.
+cronbeats_dropped_total{job_key="abc123de"} 0
+# EOF
+`
+	if out.String() != want {
+		t.Fatalf("unexpected exposition:\n%s", out.String())
+	}
+	if got := escapeLabel("a\"b\\c\nd"); got != `a\"b\\c\nd` {
+		t.Fatalf("unexpected label escaping: %s", got)
+	}
+}
@@ -0,0 +1,127 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"sync"
+)
+
+const defaultMultiParallel = 8
+
+// MultiClient pings many jobs that share one configuration. When
+// RateLimitPerSecond is set the limit applies to all jobs together, and all
+// jobs write to one buffered EventWriter stream; call Close to flush it.
+type MultiClient struct {
+	// MaxParallel bounds concurrent pings in HeartbeatAll. Defaults to 8.
+	MaxParallel int
+
+	mu      sync.RWMutex
+	options Options
+	keys    []string
+	clients map[string]*PingClient
+}
+
+type MultiResult struct {
+	Result *PingSuccess
+	Err    error
+}
+
+func NewMultiClient(jobKeys []string, opts *Options) (*MultiClient, error) {
+	options := Options{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.RateLimitPerSecond > 0 {
+		options.sharedLimiter = newTokenBucket(options.RateLimitPerSecond, float64(options.RateLimitBurst))
+	}
+	if options.EventWriter != nil {
+		options.sharedEvents = newEventStream(options.EventWriter)
+	}
+
+	m := &MultiClient{options: options, clients: map[string]*PingClient{}}
+	for _, key := range jobKeys {
+		if err := m.Register(key); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Register adds a job key. Registering a key twice is a no-op.
+func (m *MultiClient) Register(jobKey string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.clients[jobKey]; exists {
+		return nil
+	}
+	options := m.options
+	client, err := NewPingClient(jobKey, &options)
+	if err != nil {
+		return err
+	}
+	m.keys = append(m.keys, jobKey)
+	m.clients[jobKey] = client
+	return nil
+}
+
+// Client returns the client for a registered job key.
+func (m *MultiClient) Client(jobKey string) (*PingClient, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c, ok := m.clients[jobKey]
+	return c, ok
+}
+
+// HeartbeatAll pings every registered job concurrently, at most MaxParallel
+// at a time, and returns the outcome per job key.
+func (m *MultiClient) HeartbeatAll() map[string]MultiResult {
+	return m.each(func(c *PingClient) (*PingSuccess, error) { return c.Ping() })
+}
+
+func (m *MultiClient) each(fn func(*PingClient) (*PingSuccess, error)) map[string]MultiResult {
+	clients := m.registered()
+
+	parallel := m.MaxParallel
+	if parallel <= 0 {
+		parallel = defaultMultiParallel
+	}
+
+	results := make(map[string]MultiResult, len(clients))
+	var resultsMu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+	for _, client := range clients {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(c *PingClient) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			res, err := fn(c)
+			resultsMu.Lock()
+			results[c.jobKey] = MultiResult{Result: res, Err: err}
+			resultsMu.Unlock()
+		}(client)
+	}
+	wg.Wait()
+	return results
+}
+
+// Close closes every registered client, sending buffered progress and
+// flushing the shared event stream, and returns their errors joined.
+func (m *MultiClient) Close() error {
+	var errs []error
+	for _, client := range m.registered() {
+		errs = append(errs, client.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// registered returns the clients in registration order.
+func (m *MultiClient) registered() []*PingClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	clients := make([]*PingClient, 0, len(m.keys))
+	for _, key := range m.keys {
+		clients = append(clients, m.clients[key])
+	}
+	return clients
+}
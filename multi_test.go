@@ -0,0 +1,112 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type concurrencyProbe struct {
+	inFlight atomic.Int32
+	peak     atomic.Int32
+	mu       sync.Mutex
+	urls     []string
+}
+
+func (p *concurrencyProbe) Request(_ string, url string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	n := p.inFlight.Add(1)
+	defer p.inFlight.Add(-1)
+	for {
+		peak := p.peak.Load()
+		if n <= peak || p.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	p.mu.Lock()
+	p.urls = append(p.urls, url)
+	p.mu.Unlock()
+	if strings.HasSuffix(url, "/ping/missing1") {
+		return &HttpResponse{Status: 404, Body: `{"message":"Job not found"}`, Headers: map[string]string{}}, nil
+	}
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestHeartbeatAllPingsEveryJobWithBoundedParallelism(t *testing.T) {
+	probe := &concurrencyProbe{}
+	keys := []string{"job00001", "job00002", "job00003", "job00004", "job00005", "missing1"}
+	multi, err := NewMultiClient(keys, &Options{HTTPClient: probe})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	multi.MaxParallel = 2
+
+	results := multi.HeartbeatAll()
+	if len(results) != len(keys) || len(probe.urls) != len(keys) {
+		t.Fatalf("expected %d results, got %d (%d calls)", len(keys), len(results), len(probe.urls))
+	}
+	if peak := probe.peak.Load(); peak > 2 {
+		t.Fatalf("expected at most 2 concurrent pings, saw %d", peak)
+	}
+	if results["job00001"].Err != nil || !results["job00001"].Result.Ok {
+		t.Fatalf("unexpected result for job00001: %#v", results["job00001"])
+	}
+	if results["missing1"].Err == nil {
+		t.Fatal("expected error for missing1")
+	}
+}
+
+func TestMultiClientSharesRateLimiter(t *testing.T) {
+	multi, err := NewMultiClient([]string{"job00001", "job00002"}, &Options{
+		HTTPClient:         &syncStubClient{},
+		RateLimitPerSecond: 1,
+		ThrottleDrop:       true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	results := multi.HeartbeatAll()
+	throttled := 0
+	for _, r := range results {
+		if r.Result.Action == "throttled" {
+			throttled++
+		}
+	}
+	if throttled != 1 {
+		t.Fatalf("expected one job to be throttled by the shared limiter, got %d", throttled)
+	}
+}
+
+func TestMultiClientSharesOneEventStream(t *testing.T) {
+	var out bytes.Buffer
+	multi, err := NewMultiClient([]string{"job00001", "job00002"}, &Options{HTTPClient: &syncStubClient{}, EventWriter: &out})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, key := range []string{"job00001", "job00002"} {
+		client, _ := multi.Client(key)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				_, _ = client.Ping()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := multi.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	events := decodeEvents(t, out.String())
+	if len(events) != 2*200*3 {
+		t.Fatalf("expected every event to be flushed, got %d", len(events))
+	}
+}
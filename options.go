@@ -0,0 +1,1021 @@
+package cronbeatsgo
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Options struct {
+	BaseURL        string
+	TimeoutMs      int
+	MaxRetries     int
+	RetryBackoffMs int
+	RetryJitterMs  int
+	UserAgent      string
+	HTTPClient     HttpClient
+
+	// DryRun builds every request as usual but records it instead of sending
+	// it, answering each with an empty 200 response. See DryRunCalls.
+	DryRun bool
+
+	// RetryBudgetPerSecond caps retries across all requests of the client,
+	// so an outage does not multiply load by MaxRetries. When the budget is
+	// spent, failures are returned without retrying. Zero disables it.
+	RetryBudgetPerSecond float64
+
+	// DefaultEndStatus is used by End when called with an empty status.
+	// nil keeps the "success" default; a pointer to "" makes the status
+	// mandatory.
+	DefaultEndStatus *string
+
+	// BestEffortResult makes failed requests return a non-nil PingSuccess
+	// with Ok set to false alongside the error, so callers can read res.Ok
+	// unconditionally.
+	BestEffortResult bool
+
+	// RateLimitPerSecond limits how often the client sends requests, with
+	// bursts of up to RateLimitBurst (default 1). Calls over the limit wait
+	// for a slot, or with ThrottleDrop are dropped and return a result with
+	// Action "throttled" and a nil error. Zero disables the limiter.
+	RateLimitPerSecond float64
+	RateLimitBurst     int
+	ThrottleDrop       bool
+
+	// OfflineQueueSize keeps up to this many requests that failed with a
+	// network error after all retries, for replay with FlushPending. The
+	// oldest entry is dropped when the queue is full. Zero disables it.
+	OfflineQueueSize int
+
+	// ActionOptions override retry settings per action ("ping", "start",
+	// "end", "progress", "skip"). Unset fields fall back to the options
+	// above.
+	ActionOptions map[string]ActionOptions
+
+	// ProgressMaxRunes and ProgressMaxBytes cap progress messages and
+	// failure reasons. Both apply, so whichever is more restrictive wins;
+	// truncation never splits a UTF-8 character. ProgressMaxRunes defaults
+	// to 255; ProgressMaxBytes is unlimited by default.
+	ProgressMaxRunes int
+	ProgressMaxBytes int
+	// ProgressOverflow decides what happens to messages over those limits.
+	// Defaults to OverflowTruncate.
+	ProgressOverflow ProgressOverflow
+	// ProgressSkipThresholdMs is the time ProgressContext expects a progress
+	// request to take. When less than that remains before the context's
+	// deadline, the update is skipped. Defaults to TimeoutMs.
+	ProgressSkipThresholdMs int
+	// RequireProgressMessage rejects Progress calls whose message is blank
+	// with a *ValidationError. Calls that carry a seq are sequence-only
+	// updates and are still allowed without a message.
+	RequireProgressMessage bool
+	// ProgressBatchSize, when above 1, buffers Progress calls and sends them
+	// as one request with an "updates" list once that many are buffered, or
+	// on FlushProgress or Close. Buffered calls return a result with Action
+	// "buffered".
+	ProgressBatchSize int
+
+	// ProgressStreamIntervalMs is the minimum spacing between updates sent
+	// by StreamProgress. Defaults to 1000.
+	ProgressStreamIntervalMs int
+
+	// FallbackBaseURLs are tried in order after BaseURL when an attempt
+	// fails and is retried.
+	FallbackBaseURLs []string
+	// EndpointWeights, when set, picks the first endpoint of each request at
+	// random. It has one weight per endpoint: BaseURL first, then each
+	// fallback. A zero weight is never picked first but still used for
+	// failover.
+	EndpointWeights []int
+
+	// RetryMinBackoffMs is the shortest delay allowed before any retry,
+	// applied after the exponential backoff and jitter are computed.
+	RetryMinBackoffMs int
+	// RetryBackoffMultiplier is the growth factor of the exponential
+	// backoff between retries. It must be greater than 1; defaults to 2.
+	RetryBackoffMultiplier float64
+	// RetryJitterPercent adds a random delay of up to this percentage of
+	// each retry's backoff, so jitter scales with the delay. When set, it
+	// takes precedence over RetryJitterMs. It must be between 0 and 100.
+	RetryJitterPercent float64
+	// InitialDelayMs holds back the client's first request by this long,
+	// e.g. to let a dependency that was just started settle. It applies
+	// once per client, not to each call: requests made while it runs all
+	// wait for it, each ending early if its context is canceled. Defaults
+	// to 0.
+	InitialDelayMs int
+
+	RequestInterceptor RequestInterceptor
+
+	Logger  Logger
+	Metrics MetricsRecorder
+	// Clock replaces the system clock for every time-dependent feature. It
+	// is read when the client is created; UpdateOptions keeps the original.
+	Clock Clock
+	// AuditWriter receives one line per completed ping, see AuditLine.
+	AuditWriter io.Writer
+	// EventWriter receives a buffered JSON-lines stream of StreamEvents for
+	// every request, attempt, retry and outcome. Call Close to flush it.
+	EventWriter io.Writer
+
+	// SuccessDetector, when set, replaces the 2xx status check. It reports
+	// whether the response is a success and, if not, which error code to use.
+	SuccessDetector func(resp *HttpResponse, parsed map[string]any) (bool, ApiErrorCode)
+
+	// SuccessStatuses, when set, lists exactly which HTTP statuses count as
+	// success instead of the 2xx range. SuccessDetector takes precedence.
+	SuccessStatuses []int
+
+	// RetryableError inspects the decoded body of every response. When it
+	// returns true the attempt is retried even if the status says success or
+	// a non-retryable error.
+	RetryableError func(parsed map[string]any) bool
+	// RetryableBodyCodes are application error codes, read from the
+	// RetryableBodyCodeField of the decoded body (default "code"), that mark
+	// an attempt as transient. Like RetryableError they are retried whatever
+	// the status, e.g. {"code":"TEMP_LOCK"} on a 200 or 409; status-based
+	// retries still apply to other responses.
+	RetryableBodyCodes     []string
+	RetryableBodyCodeField string
+
+	// NetworkRetryable decides whether a request that got no response is
+	// retried; by default all are. Connection resets and unexpected EOFs
+	// are retried regardless, since pings are idempotent, unless
+	// DisableConnResetRetry is set.
+	NetworkRetryable      func(err error) bool
+	DisableConnResetRetry bool
+	// FailFastDNS returns DNS resolution failures without retrying, as they
+	// usually mean a misconfigured host rather than a transient blip.
+	FailFastDNS bool
+
+	// IsSuccessBody, when set, must confirm the decoded body of every
+	// successful response. A false result turns the response into a
+	// retryable CodeServer error.
+	IsSuccessBody func(parsed map[string]any) bool
+
+	// ErrorMessageExtractor returns the message of an error response from
+	// its decoded body, for servers that nest it (e.g. under error.message).
+	// Defaults to the top-level "message" string.
+	ErrorMessageExtractor func(parsed map[string]any) string
+
+	// OnRawResponse receives the unparsed response body of every HTTP
+	// response, successful or not, before it is decoded.
+	OnRawResponse func(action string, status int, body []byte)
+
+	// OnExchange is called after every HTTP attempt, including retries and
+	// DryRun, with the request as sent and either the response or the
+	// transport error. It is meant for contract tests that pin the SDK's
+	// wire behavior.
+	OnExchange func(req ExchangeInfo, resp *HttpResponse, err error)
+
+	// OnRetry is called before each retry with the 1-based retry number,
+	// why the previous attempt is being retried, and the error it produced.
+	OnRetry func(attempt int, reason RetryReason, err error)
+
+	// BackoffOverride returns the delay before retry number attempt given
+	// the default delay and the error being retried. The default is the
+	// exponential backoff with jitter, RetryMinBackoffMs and backpressure,
+	// raised to the server's Retry-After on a 429 or 503 (capped at one
+	// minute); the override's result is used as is, so it can also shorten
+	// a Retry-After.
+	BackoffOverride func(attempt int, defaultDelay time.Duration, lastErr error) time.Duration
+
+	// ClockSkewTolerance absorbs clock differences with the server in the
+	// SDK's time checks: PingAt rejects timestamps further than this in the
+	// future, and PingChecked reports overdue only once next_expected is
+	// this far in the past. Defaults to 60s.
+	ClockSkewTolerance time.Duration
+
+	// OnOverdue is called after a successful request whose response shows
+	// the job is already overdue, as reported by IsOverdue, so a worker can
+	// react as soon as it learns it is behind schedule.
+	OnOverdue func(res *PingSuccess)
+
+	// BodyPreviewBytes caps ApiError.BodyPreview. Defaults to 512.
+	BodyPreviewBytes int
+
+	// sharedLimiter replaces the per-client rate limiter, letting a
+	// MultiClient enforce one rate across all of its jobs.
+	sharedLimiter *tokenBucket
+	// sharedEvents is the EventWriter stream of a MultiClient or parent
+	// client, so clients writing to one writer share one buffer and their
+	// lines never interleave.
+	sharedEvents *eventStream
+
+	// ResponseFormat selects the Accept header and response decoder. The
+	// zero value requests and decodes JSON.
+	ResponseFormat ResponseFormat
+
+	// IncludeHostInfo adds "hostname" and "pid" to every request body. The
+	// hostname is looked up once and left out if it cannot be determined.
+	IncludeHostInfo bool
+
+	// IncludeClientSeq adds "client_seq" to every request body, a counter
+	// that starts at 1 for each client and increases by one per call, so
+	// the server can spot dropped or reordered pings. Retries of a call
+	// reuse its value.
+	IncludeClientSeq bool
+
+	// Redactor rewrites request and response bodies before they reach a log
+	// event or ApiError.BodyPreview, e.g. to strip tokens. It receives a
+	// copy and never affects what is sent. nil leaves bodies unchanged.
+	Redactor func(body []byte) []byte
+
+	// AcceptLanguage is sent as the Accept-Language header so the server
+	// can localize its messages. Empty omits the header.
+	AcceptLanguage string
+
+	// RequestContentType is the Content-Type of request bodies, for gateways
+	// that require a vendor type. Defaults to "application/json"; requests
+	// without a body send no Content-Type.
+	RequestContentType string
+
+	// ActionInUserAgent appends the action to the User-Agent of pings, e.g.
+	// "cronbeats-go-sdk/0.1.0 (action=progress)", after any custom suffix.
+	// See WithActionInUserAgent.
+	ActionInUserAgent bool
+
+	// BodyEnvelopeKey, when set, nests each request body under this key,
+	// e.g. {"data": {"message": "..."}}, for servers and gateways that
+	// expect enveloped payloads. Requests without a body are unaffected.
+	BodyEnvelopeKey string
+
+	// MaxConcurrentRequests caps how many HTTP requests the client has in
+	// flight at once. Further attempts wait for a slot or until their
+	// context is done. Zero means no limit.
+	MaxConcurrentRequests int
+
+	// IncludeResourceMetrics adds the process's heap usage and goroutine
+	// count to every request body. Reading them briefly stops the world, so
+	// the values are sampled at most once per second.
+	IncludeResourceMetrics bool
+
+	// PreserveNumbers decodes numbers in JSON responses as json.Number
+	// instead of float64, so large integer IDs in Raw keep their precision.
+	PreserveNumbers bool
+
+	// URLBuilder replaces DefaultURLBuilder to build the URL of every
+	// request. baseURL is BaseURL or the fallback being tried.
+	URLBuilder func(baseURL string, jobKey string, action string, seq *int) string
+
+	// PathPrefix is inserted between BaseURL (and each fallback) and the
+	// ping path, e.g. "/api/v1" for servers behind a path-routing proxy.
+	PathPrefix string
+
+	// MaintenanceBackoff is how long every client in the process stops
+	// sending after a 503 response with "maintenance": true. Requests in
+	// that window fail with CodeMaintenance without being sent or retried.
+	// Zero retries maintenance responses like any other 503.
+	MaintenanceBackoff time.Duration
+	// OnMaintenance is called for every maintenance response with the end
+	// of the window it opened, or the zero time without MaintenanceBackoff.
+	OnMaintenance func(until time.Time)
+
+	// FirstAttemptTimeoutMs replaces TimeoutMs for the first attempt of each
+	// request, which may need longer for DNS and TLS setup. Retries use
+	// TimeoutMs. Zero uses TimeoutMs throughout.
+	FirstAttemptTimeoutMs int
+
+	// LatencyPacing slows the client down while the server responds slowly,
+	// even when requests succeed. Zero Threshold disables it.
+	LatencyPacing LatencyPacingOptions
+
+	// AdaptiveThrottle is cooperative backpressure for a struggling server:
+	// while calls keep failing with retryable errors after their retries,
+	// each new call waits longer before it is sent, until one succeeds.
+	// This is separate from the retries within a call. Zero BaseDelay
+	// disables it; see ThrottleLevel.
+	AdaptiveThrottle AdaptiveThrottleOptions
+
+	// StrictConfig makes the constructor reject option combinations where
+	// a setting has no effect, such as ThrottleDrop without a rate limit or
+	// ActionOptions for an unknown action.
+	StrictConfig bool
+
+	// TraceIDHeader names the header carrying CallOptions.TraceID. Defaults
+	// to "X-Trace-Id".
+	TraceIDHeader string
+
+	// OnFirstSuccess is called once, with the result of the first request
+	// that succeeds, e.g. to mark a service ready.
+	OnFirstSuccess func(res *PingSuccess)
+
+	// OnSuccessRaw is called with the decoded body of every successful
+	// response before it is normalized into a PingSuccess, e.g. to pick out
+	// server fields the SDK does not model. replayed is true for pings
+	// delivered from the offline queue by FlushPending.
+	OnSuccessRaw func(action string, parsed map[string]any, replayed bool)
+
+	// QuietHours are daily windows during which Ping and Progress calls,
+	// including heartbeats and steps, are not sent and return a result with
+	// Action "skipped". Start, end and fail always go through.
+	QuietHours []TimeRange
+}
+
+// LatencyPacingOptions configure latency-based pacing. The client averages
+// response latency (each response weighs 30%); while the average exceeds
+// Threshold, each request waits until (average - Threshold) * Factor has
+// passed since the previous response, never more than MaxSpacing.
+type LatencyPacingOptions struct {
+	Threshold time.Duration
+	// Factor defaults to 10.
+	Factor float64
+	// MaxSpacing defaults to 30s.
+	MaxSpacing time.Duration
+}
+
+// AdaptiveThrottleOptions configure adaptive throttling. After n calls in a
+// row fail, the next call waits until BaseDelay * Multiplier^(n-1), never
+// more than MaxDelay, has passed since the last failure.
+type AdaptiveThrottleOptions struct {
+	BaseDelay time.Duration
+	// Multiplier defaults to 2 and must not be below 1.
+	Multiplier float64
+	// MaxDelay defaults to 5m.
+	MaxDelay time.Duration
+}
+
+// ProgressOverflow is the handling of progress messages, end messages and
+// failure reasons longer than ProgressMaxRunes or ProgressMaxBytes.
+type ProgressOverflow string
+
+const (
+	// OverflowTruncate cuts the message to fit.
+	OverflowTruncate ProgressOverflow = "truncate"
+	// OverflowError rejects the call with a *ValidationError.
+	OverflowError ProgressOverflow = "error"
+)
+
+// ResponseFormat describes an alternative wire format for responses, such as
+// msgpack or protobuf. Decode must turn a response body into the same
+// key/value shape the JSON API returns.
+type ResponseFormat struct {
+	Accept string
+	Decode func(body []byte) (map[string]any, error)
+}
+
+type ActionOptions struct {
+	TimeoutMs      int
+	MaxRetries     *int
+	RetryBackoffMs int
+}
+
+// WithActionOptions registers overrides for a single action.
+func (o *Options) WithActionOptions(action string, opts ActionOptions) *Options {
+	if o.ActionOptions == nil {
+		o.ActionOptions = map[string]ActionOptions{}
+	}
+	o.ActionOptions[action] = opts
+	return o
+}
+
+func (o *Options) WithRetryableError(fn func(parsed map[string]any) bool) *Options {
+	o.RetryableError = fn
+	return o
+}
+
+// retryableBody reports whether RetryableError or RetryableBodyCodes mark the
+// decoded body as transient.
+func (cfg *clientConfig) retryableBody(parsed map[string]any) bool {
+	if cfg.retryableError != nil && cfg.retryableError(parsed) {
+		return true
+	}
+	if code, ok := parsed[cfg.codeField]; ok && code != nil && len(cfg.retryableCodes) > 0 {
+		return cfg.retryableCodes[fmt.Sprint(code)]
+	}
+	return false
+}
+
+const defaultUserAgent = "cronbeats-go-sdk/0.1.0"
+
+var readBuildInfo = debug.ReadBuildInfo
+
+// WithBuildInfoUserAgent appends the main module's path and version, e.g.
+// "example.com/worker/v2.3.1", to the User-Agent. Nothing is added when the
+// binary carries no version information, as with go run.
+func (o *Options) WithBuildInfoUserAgent() *Options {
+	info, ok := readBuildInfo()
+	if !ok || info.Main.Path == "" || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return o
+	}
+	o.UserAgent = defaultString(o.UserAgent, defaultUserAgent) + " " + info.Main.Path + "/" + info.Main.Version
+	return o
+}
+
+// WithActionInUserAgent sets ActionInUserAgent, so the server can segment
+// traffic by action without inspecting bodies.
+func (o *Options) WithActionInUserAgent() *Options {
+	o.ActionInUserAgent = true
+	return o
+}
+
+// userAgentFor returns the User-Agent for a request of action.
+func (cfg *clientConfig) userAgentFor(action string) string {
+	if agent, ok := cfg.actionAgents[action]; ok {
+		return agent
+	}
+	return cfg.userAgent
+}
+
+type clientConfig struct {
+	baseURL          string
+	endpoints        []string
+	weights          []int
+	timeoutMs        int
+	maxRetries       int
+	retryBackoffMs   int
+	retryJitterMs    int
+	jitterPercent    float64
+	retryMinMs       int
+	retryMultiplier  float64
+	initialDelay     time.Duration
+	retryBudget      *tokenBucket
+	actions          map[string]ActionOptions
+	bestEffort       bool
+	defaultEndStatus string
+	limiter          *tokenBucket
+	throttleDrop     bool
+	queueSize        int
+	streamInterval   time.Duration
+	maxRunes         int
+	maxBytes         int
+	userAgent        string
+	actionAgents     map[string]string
+	httpClient       HttpClient
+	interceptor      RequestInterceptor
+	logger           Logger
+	metrics          MetricsRecorder
+	auditWriter      io.Writer
+	successDetect    func(resp *HttpResponse, parsed map[string]any) (bool, ApiErrorCode)
+	successStatuses  map[int]bool
+	events           *eventStream
+	retryableError   func(parsed map[string]any) bool
+	retryableCodes   map[string]bool
+	codeField        string
+	networkRetryable func(err error) bool
+	backoffOverride  func(attempt int, defaultDelay time.Duration, lastErr error) time.Duration
+	noResetRetry     bool
+	failFastDNS      bool
+	isSuccessBody    func(parsed map[string]any) bool
+	errorMessage     func(parsed map[string]any) string
+	onRawResponse    func(action string, status int, body []byte)
+	onExchange       func(req ExchangeInfo, resp *HttpResponse, err error)
+	onRetry          func(attempt int, reason RetryReason, err error)
+	previewBytes     int
+	clockSkew        time.Duration
+	accept           string
+	decode           func(body []byte) (map[string]any, error)
+	hostInfo         map[string]any
+	clientSeq        bool
+	dryRun           bool
+	redactor         func(body []byte) []byte
+	acceptLanguage   string
+	contentType      string
+	envelopeKey      string
+	slots            chan struct{}
+	resourceMetrics  bool
+	preserveNumbers  bool
+	urlBuilder       func(baseURL string, jobKey string, action string, seq *int) string
+	maintenance      time.Duration
+	onMaintenance    func(until time.Time)
+	firstTimeoutMs   int
+	pacer            *latencyPacer
+	throttle         *adaptiveThrottle
+	traceIDHeader    string
+	onFirstSuccess   func(res *PingSuccess)
+	onSuccessRaw     func(action string, parsed map[string]any, replayed bool)
+	onOverdue        func(res *PingSuccess)
+	overflow         ProgressOverflow
+	quietHours       []TimeRange
+	skipThresholdMs  int
+	requireMessage   bool
+	progressBatch    int
+}
+
+// hostname is resolved on first use and reused by every config rebuild.
+var hostname = sync.OnceValues(os.Hostname)
+
+func defaultOverflow(mode ProgressOverflow) ProgressOverflow {
+	if mode == "" {
+		return OverflowTruncate
+	}
+	return mode
+}
+
+func newClientConfig(options Options, defaultHTTP HttpClient) (*clientConfig, error) {
+	if options.StrictConfig {
+		if problems := strictProblems(options); len(problems) > 0 {
+			return nil, &ValidationError{Message: "Contradictory options: " + strings.Join(problems, "; ") + "."}
+		}
+	}
+	if options.TimeoutMs < 0 {
+		return nil, &ValidationError{Message: "TimeoutMs must not be negative."}
+	}
+	if options.FirstAttemptTimeoutMs < 0 {
+		return nil, &ValidationError{Message: "FirstAttemptTimeoutMs must not be negative."}
+	}
+	if options.MaxRetries < 0 {
+		return nil, &ValidationError{Message: "MaxRetries must not be negative."}
+	}
+	if options.RetryBackoffMs < 0 {
+		return nil, &ValidationError{Message: "RetryBackoffMs must not be negative."}
+	}
+	if options.RetryJitterMs < 0 {
+		return nil, &ValidationError{Message: "RetryJitterMs must not be negative."}
+	}
+	if options.RetryMinBackoffMs < 0 {
+		return nil, &ValidationError{Message: "RetryMinBackoffMs must not be negative."}
+	}
+	if !(options.RetryJitterPercent >= 0 && options.RetryJitterPercent <= 100) {
+		return nil, &ValidationError{Message: "RetryJitterPercent must be between 0 and 100."}
+	}
+	if (options.RetryBackoffMultiplier != 0 && !(options.RetryBackoffMultiplier > 1)) || math.IsInf(options.RetryBackoffMultiplier, 0) {
+		return nil, &ValidationError{Message: "RetryBackoffMultiplier must be greater than 1."}
+	}
+	retryMultiplier := options.RetryBackoffMultiplier
+	if retryMultiplier == 0 {
+		retryMultiplier = 2
+	}
+
+	if options.ProgressMaxRunes < 0 || options.ProgressMaxBytes < 0 {
+		return nil, &ValidationError{Message: "ProgressMaxRunes and ProgressMaxBytes must not be negative."}
+	}
+	if options.ProgressOverflow != "" && options.ProgressOverflow != OverflowTruncate && options.ProgressOverflow != OverflowError {
+		return nil, &ValidationError{Message: `ProgressOverflow must be "truncate" or "error".`}
+	}
+	if options.InitialDelayMs < 0 {
+		return nil, &ValidationError{Message: "InitialDelayMs must not be negative."}
+	}
+	if options.ProgressBatchSize < 0 {
+		return nil, &ValidationError{Message: "ProgressBatchSize must not be negative."}
+	}
+	if options.ProgressSkipThresholdMs < 0 {
+		return nil, &ValidationError{Message: "ProgressSkipThresholdMs must not be negative."}
+	}
+	if options.ProgressStreamIntervalMs < 0 {
+		return nil, &ValidationError{Message: "ProgressStreamIntervalMs must not be negative."}
+	}
+	if options.ClockSkewTolerance < 0 {
+		return nil, &ValidationError{Message: "ClockSkewTolerance must not be negative."}
+	}
+	clockSkew := options.ClockSkewTolerance
+	if clockSkew == 0 {
+		clockSkew = 60 * time.Second
+	}
+	if options.BodyPreviewBytes < 0 {
+		return nil, &ValidationError{Message: "BodyPreviewBytes must not be negative."}
+	}
+	if (options.ResponseFormat.Accept == "") != (options.ResponseFormat.Decode == nil) {
+		return nil, &ValidationError{Message: "ResponseFormat requires both Accept and Decode."}
+	}
+
+	if options.MaintenanceBackoff < 0 {
+		return nil, &ValidationError{Message: "MaintenanceBackoff must not be negative."}
+	}
+	if options.RetryBudgetPerSecond < 0 {
+		return nil, &ValidationError{Message: "RetryBudgetPerSecond must not be negative."}
+	}
+
+	actions := make(map[string]ActionOptions, len(options.ActionOptions))
+	for action, ao := range options.ActionOptions {
+		if ao.TimeoutMs < 0 || ao.RetryBackoffMs < 0 || (ao.MaxRetries != nil && *ao.MaxRetries < 0) {
+			return nil, &ValidationError{Message: fmt.Sprintf("ActionOptions for %q must not be negative.", action)}
+		}
+		actions[action] = ao
+	}
+
+	defaultEndStatus := "success"
+	if options.DefaultEndStatus != nil {
+		defaultEndStatus = ""
+		if strings.TrimSpace(*options.DefaultEndStatus) != "" {
+			status, err := normalizeEndStatus(*options.DefaultEndStatus)
+			if err != nil {
+				return nil, &ValidationError{Message: `DefaultEndStatus must be "success", "fail", "canceled", or empty.`}
+			}
+			defaultEndStatus = status
+		}
+	}
+	if options.OfflineQueueSize < 0 {
+		return nil, &ValidationError{Message: "OfflineQueueSize must not be negative."}
+	}
+	if options.RateLimitPerSecond < 0 || options.RateLimitBurst < 0 {
+		return nil, &ValidationError{Message: "RateLimitPerSecond and RateLimitBurst must not be negative."}
+	}
+	limiter := options.sharedLimiter
+	if limiter == nil && options.RateLimitPerSecond > 0 {
+		limiter = newTokenBucket(options.RateLimitPerSecond, float64(options.RateLimitBurst))
+	}
+
+	var retryBudget *tokenBucket
+	if options.RetryBudgetPerSecond > 0 {
+		retryBudget = newTokenBucket(options.RetryBudgetPerSecond, options.RetryBudgetPerSecond)
+	}
+
+	baseURL := strings.TrimRight(defaultString(options.BaseURL, "https://cronbeats.io"), "/")
+	endpoints := []string{joinURLPath(baseURL, options.PathPrefix)}
+	for _, fallback := range options.FallbackBaseURLs {
+		if strings.TrimSpace(fallback) == "" {
+			return nil, &ValidationError{Message: "FallbackBaseURLs must not contain empty URLs."}
+		}
+		endpoints = append(endpoints, joinURLPath(fallback, options.PathPrefix))
+	}
+	if len(options.EndpointWeights) > 0 {
+		if len(options.EndpointWeights) != len(endpoints) {
+			return nil, &ValidationError{Message: "EndpointWeights must have one weight for BaseURL and each fallback."}
+		}
+		total := 0
+		for _, w := range options.EndpointWeights {
+			if w < 0 {
+				return nil, &ValidationError{Message: "EndpointWeights must not be negative."}
+			}
+			total += w
+		}
+		if total == 0 {
+			return nil, &ValidationError{Message: "EndpointWeights must contain a positive weight."}
+		}
+	}
+
+	httpClient := options.HTTPClient
+	if httpClient == nil {
+		httpClient = defaultHTTP
+	}
+
+	if options.MaxConcurrentRequests < 0 {
+		return nil, &ValidationError{Message: "MaxConcurrentRequests must not be negative."}
+	}
+	var slots chan struct{}
+	if options.MaxConcurrentRequests > 0 {
+		slots = make(chan struct{}, options.MaxConcurrentRequests)
+	}
+
+	events := options.sharedEvents
+	if events == nil || events.w != options.EventWriter {
+		events = nil
+		if options.EventWriter != nil {
+			events = newEventStream(options.EventWriter)
+		}
+	}
+
+	var successStatuses map[int]bool
+	for _, status := range options.SuccessStatuses {
+		if status < 100 || status > 599 {
+			return nil, &ValidationError{Message: "SuccessStatuses must be HTTP status codes between 100 and 599."}
+		}
+		if successStatuses == nil {
+			successStatuses = map[int]bool{}
+		}
+		successStatuses[status] = true
+	}
+
+	for _, window := range options.QuietHours {
+		if err := window.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	pacing := options.LatencyPacing
+	if pacing.Threshold < 0 || pacing.Factor < 0 || pacing.MaxSpacing < 0 || math.IsNaN(pacing.Factor) || math.IsInf(pacing.Factor, 0) {
+		return nil, &ValidationError{Message: "LatencyPacing values must not be negative."}
+	}
+	var pacer *latencyPacer
+	if pacing.Threshold > 0 {
+		pacer = &latencyPacer{threshold: pacing.Threshold, factor: pacing.Factor, maxSpacing: pacing.MaxSpacing}
+		if pacer.factor == 0 {
+			pacer.factor = 10
+		}
+		if pacer.maxSpacing == 0 {
+			pacer.maxSpacing = 30 * time.Second
+		}
+	}
+
+	adaptive := options.AdaptiveThrottle
+	if adaptive.BaseDelay < 0 || adaptive.MaxDelay < 0 || math.IsNaN(adaptive.Multiplier) || math.IsInf(adaptive.Multiplier, 0) || (adaptive.Multiplier != 0 && adaptive.Multiplier < 1) {
+		return nil, &ValidationError{Message: "AdaptiveThrottle delays must not be negative and Multiplier must be at least 1."}
+	}
+	var throttle *adaptiveThrottle
+	if adaptive.BaseDelay > 0 {
+		throttle = &adaptiveThrottle{base: adaptive.BaseDelay, multiplier: adaptive.Multiplier, maxDelay: adaptive.MaxDelay}
+		if throttle.multiplier == 0 {
+			throttle.multiplier = 2
+		}
+		if throttle.maxDelay == 0 {
+			throttle.maxDelay = 5 * time.Minute
+		}
+	}
+
+	var retryableCodes map[string]bool
+	for _, code := range options.RetryableBodyCodes {
+		if strings.TrimSpace(code) == "" {
+			return nil, &ValidationError{Message: "RetryableBodyCodes must not contain blank codes."}
+		}
+		if retryableCodes == nil {
+			retryableCodes = map[string]bool{}
+		}
+		retryableCodes[code] = true
+	}
+
+	userAgent := defaultString(options.UserAgent, defaultUserAgent)
+	var actionAgents map[string]string
+	if options.ActionInUserAgent && !strings.Contains(userAgent, "(action=") {
+		actionAgents = make(map[string]string, len(knownActions))
+		for action := range knownActions {
+			actionAgents[action] = userAgent + " (action=" + action + ")"
+		}
+	}
+
+	var hostInfo map[string]any
+	if options.IncludeHostInfo {
+		hostInfo = map[string]any{"pid": os.Getpid()}
+		if name, err := hostname(); err == nil && name != "" {
+			hostInfo["hostname"] = name
+		}
+	}
+
+	return &clientConfig{
+		baseURL:          baseURL,
+		endpoints:        endpoints,
+		weights:          append([]int(nil), options.EndpointWeights...),
+		timeoutMs:        defaultInt(options.TimeoutMs, 5000),
+		maxRetries:       defaultInt(options.MaxRetries, 2),
+		retryBackoffMs:   defaultInt(options.RetryBackoffMs, 250),
+		retryJitterMs:    defaultInt(options.RetryJitterMs, 100),
+		jitterPercent:    options.RetryJitterPercent,
+		retryMinMs:       options.RetryMinBackoffMs,
+		retryMultiplier:  retryMultiplier,
+		retryBudget:      retryBudget,
+		actions:          actions,
+		bestEffort:       options.BestEffortResult,
+		defaultEndStatus: defaultEndStatus,
+		limiter:          limiter,
+		throttleDrop:     options.ThrottleDrop,
+		queueSize:        options.OfflineQueueSize,
+		maxRunes:         defaultInt(options.ProgressMaxRunes, 255),
+		maxBytes:         options.ProgressMaxBytes,
+		streamInterval:   time.Duration(defaultInt(options.ProgressStreamIntervalMs, 1000)) * time.Millisecond,
+		userAgent:        userAgent,
+		initialDelay:     time.Duration(options.InitialDelayMs) * time.Millisecond,
+		actionAgents:     actionAgents,
+		httpClient:       httpClient,
+		interceptor:      options.RequestInterceptor,
+		logger:           options.Logger,
+		metrics:          options.Metrics,
+		auditWriter:      options.AuditWriter,
+		successDetect:    options.SuccessDetector,
+		successStatuses:  successStatuses,
+		events:           events,
+		retryableError:   options.RetryableError,
+		retryableCodes:   retryableCodes,
+		codeField:        defaultString(strings.TrimSpace(options.RetryableBodyCodeField), "code"),
+		networkRetryable: options.NetworkRetryable,
+		backoffOverride:  options.BackoffOverride,
+		noResetRetry:     options.DisableConnResetRetry,
+		failFastDNS:      options.FailFastDNS,
+		isSuccessBody:    options.IsSuccessBody,
+		errorMessage:     options.ErrorMessageExtractor,
+		onRawResponse:    options.OnRawResponse,
+		onExchange:       options.OnExchange,
+		onRetry:          options.OnRetry,
+		previewBytes:     defaultInt(options.BodyPreviewBytes, 512),
+		clockSkew:        clockSkew,
+		accept:           defaultString(options.ResponseFormat.Accept, "application/json"),
+		decode:           options.ResponseFormat.Decode,
+		hostInfo:         hostInfo,
+		clientSeq:        options.IncludeClientSeq,
+		dryRun:           options.DryRun,
+		redactor:         options.Redactor,
+		acceptLanguage:   strings.TrimSpace(options.AcceptLanguage),
+		contentType:      defaultString(strings.TrimSpace(options.RequestContentType), "application/json"),
+		envelopeKey:      strings.TrimSpace(options.BodyEnvelopeKey),
+		slots:            slots,
+		resourceMetrics:  options.IncludeResourceMetrics,
+		preserveNumbers:  options.PreserveNumbers,
+		urlBuilder:       options.URLBuilder,
+		maintenance:      options.MaintenanceBackoff,
+		onMaintenance:    options.OnMaintenance,
+		firstTimeoutMs:   options.FirstAttemptTimeoutMs,
+		pacer:            pacer,
+		throttle:         throttle,
+		traceIDHeader:    defaultString(options.TraceIDHeader, "X-Trace-Id"),
+		onFirstSuccess:   options.OnFirstSuccess,
+		onSuccessRaw:     options.OnSuccessRaw,
+		onOverdue:        options.OnOverdue,
+		overflow:         defaultOverflow(options.ProgressOverflow),
+		quietHours:       append([]TimeRange(nil), options.QuietHours...),
+		skipThresholdMs:  options.ProgressSkipThresholdMs,
+		requireMessage:   options.RequireProgressMessage,
+		progressBatch:    options.ProgressBatchSize,
+	}, nil
+}
+
+// UpdateOptions applies mutate to a copy of the client's options and swaps in
+// the result. If the mutated options are invalid the error is returned and
+// the previous configuration stays in effect. The default transport is kept
+// across updates so pooled connections survive.
+func (c *PingClient) UpdateOptions(mutate func(*Options)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	next := cloneOptions(c.options)
+	mutate(&next)
+
+	cfg, err := newClientConfig(next, c.defaultHTTP)
+	if err != nil {
+		return err
+	}
+	cfg.carryOver(c.cfg, c.options, next)
+	c.options = next
+	c.cfg = cfg
+	return nil
+}
+
+// carryOver keeps the stateful parts of prev, built from the options before,
+// whose settings are unchanged in after: the rate-limit bucket, retry budget,
+// concurrency slots, latency pacer, adaptive throttle and event stream. A
+// replaced event stream is flushed.
+func (cfg *clientConfig) carryOver(prev *clientConfig, before Options, after Options) {
+	if before.RateLimitPerSecond == after.RateLimitPerSecond && before.RateLimitBurst == after.RateLimitBurst && before.sharedLimiter == after.sharedLimiter {
+		cfg.limiter = prev.limiter
+	}
+	if before.RetryBudgetPerSecond == after.RetryBudgetPerSecond {
+		cfg.retryBudget = prev.retryBudget
+	}
+	if before.MaxConcurrentRequests == after.MaxConcurrentRequests {
+		cfg.slots = prev.slots
+	}
+	if before.LatencyPacing == after.LatencyPacing {
+		cfg.pacer = prev.pacer
+	}
+	if before.AdaptiveThrottle == after.AdaptiveThrottle {
+		cfg.throttle = prev.throttle
+	}
+	if prev.events != nil {
+		if cfg.events != nil && prev.events.w == cfg.events.w {
+			cfg.events = prev.events
+		} else {
+			_ = prev.events.flush()
+		}
+	}
+}
+
+var knownActions = map[string]bool{"ping": true, "start": true, "end": true, "progress": true, "skip": true}
+
+// strictProblems lists settings that cannot take effect given the rest of
+// options.
+func strictProblems(options Options) []string {
+	var problems []string
+	if options.RateLimitPerSecond == 0 && (options.ThrottleDrop || options.RateLimitBurst > 0) {
+		problems = append(problems, "ThrottleDrop and RateLimitBurst require RateLimitPerSecond")
+	}
+	if options.LatencyPacing.Threshold == 0 && (options.LatencyPacing.Factor != 0 || options.LatencyPacing.MaxSpacing != 0) {
+		problems = append(problems, "LatencyPacing.Factor and MaxSpacing require LatencyPacing.Threshold")
+	}
+	actions := make([]string, 0, len(options.ActionOptions))
+	for action := range options.ActionOptions {
+		if !knownActions[action] {
+			actions = append(actions, action)
+		}
+	}
+	sort.Strings(actions)
+	for _, action := range actions {
+		problems = append(problems, fmt.Sprintf("ActionOptions has unknown action %q", action))
+	}
+	if len(options.EndpointWeights) > 0 && len(options.FallbackBaseURLs) == 0 {
+		problems = append(problems, "EndpointWeights require FallbackBaseURLs")
+	}
+	if options.FirstAttemptTimeoutMs > 0 && options.FirstAttemptTimeoutMs == options.TimeoutMs {
+		problems = append(problems, "FirstAttemptTimeoutMs equals TimeoutMs")
+	}
+	if options.DryRun && options.HTTPClient != nil {
+		problems = append(problems, "HTTPClient is never called with DryRun")
+	}
+	return problems
+}
+
+// Config returns the options in effect with defaults filled in, e.g.
+// TimeoutMs is 5000 when it was left at zero. Maps and slices are copied.
+func (c *PingClient) Config() Options {
+	c.mu.RLock()
+	opts, cfg := c.options, c.cfg
+	c.mu.RUnlock()
+
+	opts.BaseURL = cfg.baseURL
+	opts.TimeoutMs = cfg.timeoutMs
+	opts.MaxRetries = cfg.maxRetries
+	opts.RetryBackoffMs = cfg.retryBackoffMs
+	opts.RetryJitterMs = cfg.retryJitterMs
+	opts.RetryJitterPercent = cfg.jitterPercent
+	opts.RetryBackoffMultiplier = cfg.retryMultiplier
+	opts.UserAgent = cfg.userAgent
+	opts.HTTPClient = cfg.httpClient
+	opts.ProgressMaxRunes = cfg.maxRunes
+	opts.ProgressOverflow = cfg.overflow
+	opts.ProgressSkipThresholdMs = cfg.progressSkipThreshold()
+	opts.ProgressStreamIntervalMs = int(cfg.streamInterval / time.Millisecond)
+	opts.ClockSkewTolerance = cfg.clockSkew
+	opts.BodyPreviewBytes = cfg.previewBytes
+	opts.TraceIDHeader = cfg.traceIDHeader
+	opts.RequestContentType = cfg.contentType
+	if opts.RateLimitPerSecond > 0 && opts.RateLimitBurst == 0 {
+		opts.RateLimitBurst = 1
+	}
+	if opts.DefaultEndStatus != nil {
+		status := cfg.defaultEndStatus
+		opts.DefaultEndStatus = &status
+	}
+
+	return cloneOptions(opts)
+}
+
+// cloneOptions copies the maps, slices and pointers of opts, so changes to
+// the copy never reach the original.
+func cloneOptions(opts Options) Options {
+	opts.FallbackBaseURLs = append([]string(nil), opts.FallbackBaseURLs...)
+	opts.EndpointWeights = append([]int(nil), opts.EndpointWeights...)
+	opts.QuietHours = append([]TimeRange(nil), opts.QuietHours...)
+	opts.SuccessStatuses = append([]int(nil), opts.SuccessStatuses...)
+	opts.RetryableBodyCodes = append([]string(nil), opts.RetryableBodyCodes...)
+	if opts.DefaultEndStatus != nil {
+		status := *opts.DefaultEndStatus
+		opts.DefaultEndStatus = &status
+	}
+	if opts.ActionOptions != nil {
+		actions := make(map[string]ActionOptions, len(opts.ActionOptions))
+		for action, ao := range opts.ActionOptions {
+			if ao.MaxRetries != nil {
+				maxRetries := *ao.MaxRetries
+				ao.MaxRetries = &maxRetries
+			}
+			actions[action] = ao
+		}
+		opts.ActionOptions = actions
+	}
+	return opts
+}
+
+// forAction returns cfg with the overrides registered for action applied.
+func (cfg *clientConfig) forAction(action string) *clientConfig {
+	ao, ok := cfg.actions[action]
+	if !ok {
+		return cfg
+	}
+	out := *cfg
+	if ao.TimeoutMs > 0 {
+		out.timeoutMs = ao.TimeoutMs
+	}
+	if ao.MaxRetries != nil {
+		out.maxRetries = *ao.MaxRetries
+	}
+	if ao.RetryBackoffMs > 0 {
+		out.retryBackoffMs = ao.RetryBackoffMs
+	}
+	return &out
+}
+
+func (cfg *clientConfig) truncateMessage(msg string) string {
+	return truncateMessage(msg, cfg.maxRunes, cfg.maxBytes)
+}
+
+// fitMessage applies the message caps according to cfg.overflow.
+func (cfg *clientConfig) fitMessage(msg string) (string, error) {
+	fitted := cfg.truncateMessage(msg)
+	if fitted != msg && cfg.overflow == OverflowError {
+		limit := fmt.Sprintf("%d characters", cfg.maxRunes)
+		if cfg.maxBytes > 0 {
+			limit += fmt.Sprintf(" or %d bytes", cfg.maxBytes)
+		}
+		return "", &ValidationError{Message: "Message exceeds the limit of " + limit + "."}
+	}
+	return fitted, nil
+}
+
+// decodeBody parses a response body. An empty body, as sent with 204 No
+// Content, decodes to an empty map rather than a parse error.
+func (cfg *clientConfig) decodeBody(raw string) map[string]any {
+	if strings.TrimSpace(raw) == "" {
+		return map[string]any{}
+	}
+	if cfg.decode == nil {
+		return safeJSON(raw, cfg.preserveNumbers)
+	}
+	decoded, err := cfg.decode([]byte(raw))
+	if err != nil || decoded == nil {
+		return map[string]any{"message": "Invalid response body"}
+	}
+	return decoded
+}
+
+func (cfg *clientConfig) progressSkipThreshold() int {
+	return defaultInt(cfg.skipThresholdMs, cfg.timeoutMs)
+}
+
+func (c *PingClient) config() *clientConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cfg
+}
@@ -0,0 +1,491 @@
+package cronbeatsgo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+	"testing"
+	"time"
+)
+
+type headerCaptureClient struct {
+	headers   []map[string]string
+	timeouts  []int
+	responses []stubResponse
+}
+
+func (h *headerCaptureClient) Request(_ string, _ string, headers map[string]string, _ []byte, timeoutMs int) (*HttpResponse, error) {
+	h.headers = append(h.headers, headers)
+	h.timeouts = append(h.timeouts, timeoutMs)
+	if len(h.responses) == 0 {
+		return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+	}
+	next := h.responses[0]
+	h.responses = h.responses[1:]
+	return &HttpResponse{Status: next.status, Body: next.body, Headers: map[string]string{}}, nil
+}
+
+func TestUpdateOptionsAppliesNewTimeout(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, &Options{TimeoutMs: 1000})
+
+	if err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = 9000 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.timeouts[0] != 9000 {
+		t.Fatalf("expected timeout 9000, got %d", http.timeouts[0])
+	}
+}
+
+func TestUpdateOptionsRejectsInvalidConfig(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, &Options{TimeoutMs: 1000})
+
+	err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = -1 })
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.timeouts[0] != 1000 {
+		t.Fatalf("expected previous timeout 1000 to remain, got %d", http.timeouts[0])
+	}
+}
+
+func TestUpdateOptionsRollsBackFailedMutation(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, (&Options{}).WithActionOptions("ping", ActionOptions{TimeoutMs: 2000}))
+
+	err := client.UpdateOptions(func(o *Options) { o.WithActionOptions("progress", ActionOptions{TimeoutMs: -1}) })
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if actions := client.Config().ActionOptions; len(actions) != 1 || actions["ping"].TimeoutMs != 2000 {
+		t.Fatalf("expected the rejected action options to be rolled back, got %v", actions)
+	}
+	if err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = 3000 }); err != nil {
+		t.Fatalf("expected a later valid update to succeed, got %v", err)
+	}
+	if got := client.Config().TimeoutMs; got != 3000 {
+		t.Fatalf("expected TimeoutMs 3000, got %d", got)
+	}
+}
+
+func TestUpdateOptionsKeepsDefaultTransport(t *testing.T) {
+	client, err := NewPingClient("abc123de", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	before := client.config().httpClient
+	if err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = 2000 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.config().httpClient != before {
+		t.Fatal("expected default transport to be reused after update")
+	}
+}
+
+func TestUpdateOptionsKeepsLimitState(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{}`}, {status: 200, body: `{}`}, {status: 503, body: `{}`}}}
+	client := newTestClient(t, http, &Options{
+		RateLimitPerSecond:    1,
+		RateLimitBurst:        3,
+		ThrottleDrop:          true,
+		MaxConcurrentRequests: 2,
+		AdaptiveThrottle:      AdaptiveThrottleOptions{BaseDelay: time.Millisecond},
+	})
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+	client = client.With(CallOptions{NoRetry: true})
+
+	for i := 0; i < 3; i++ {
+		_, _ = client.Ping()
+	}
+	slots := client.config().slots
+	if err := client.UpdateOptions(func(o *Options) { o.TimeoutMs = 9000 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := client.Ping(); err != nil || res.Action != "throttled" {
+		t.Fatalf("expected the drained bucket to survive the update, got %#v, %v", res, err)
+	}
+	if client.config().slots != slots {
+		t.Fatalf("expected in-flight requests to keep counting against the same slots")
+	}
+	if client.ThrottleLevel() != 1 {
+		t.Fatalf("expected the throttle level to survive the update, got %d", client.ThrottleLevel())
+	}
+
+	if err := client.UpdateOptions(func(o *Options) { o.RateLimitBurst = 5 }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := client.Ping(); err != nil || res.Action == "throttled" {
+		t.Fatalf("expected a changed limit to start a fresh bucket, got %#v, %v", res, err)
+	}
+}
+
+func TestResponseFormatSetsAcceptAndDecodes(t *testing.T) {
+	http := &headerCaptureClient{responses: []stubResponse{{status: 200, body: "action=ping;processing_time_ms=4"}}}
+	client := newTestClient(t, http, &Options{
+		ResponseFormat: ResponseFormat{
+			Accept: "application/x-test",
+			Decode: func(body []byte) (map[string]any, error) {
+				out := map[string]any{}
+				for _, pair := range strings.Split(string(body), ";") {
+					key, value, _ := strings.Cut(pair, "=")
+					out[key] = value
+				}
+				return out, nil
+			},
+		},
+	})
+
+	res, err := client.Ping()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.headers[0]["Accept"] != "application/x-test" {
+		t.Fatalf("unexpected Accept header: %q", http.headers[0]["Accept"])
+	}
+	if res.Action != "ping" || res.ProcessingTimeMs != 4 {
+		t.Fatalf("unexpected normalized payload: %#v", res)
+	}
+}
+
+func TestResponseFormatRequiresDecoder(t *testing.T) {
+	_, err := NewPingClient("abc123de", &Options{ResponseFormat: ResponseFormat{Accept: "application/msgpack"}})
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+}
+
+func TestActionOptionsOverrideGlobalSettings(t *testing.T) {
+	http := &headerCaptureClient{responses: []stubResponse{
+		{status: 500, body: `{}`},
+		{status: 500, body: `{}`},
+	}}
+	noRetries := 0
+	opts := (&Options{TimeoutMs: 1000, MaxRetries: 2}).
+		WithActionOptions("progress", ActionOptions{TimeoutMs: 8000, MaxRetries: &noRetries})
+	client := newTestClient(t, http, opts)
+
+	if _, err := client.Progress(nil, "step"); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(http.timeouts) != 1 || http.timeouts[0] != 8000 {
+		t.Fatalf("expected a single progress attempt with timeout 8000, got %v", http.timeouts)
+	}
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.timeouts[1] != 1000 {
+		t.Fatalf("expected ping to use global timeout, got %d", http.timeouts[1])
+	}
+}
+
+func TestRequestInterceptorSeesAttemptAndPreviousError(t *testing.T) {
+	http := &headerCaptureClient{responses: []stubResponse{{status: 503, body: `{"message":"busy"}`}}}
+	var attempts []int
+	var prevErrs []error
+	client := newTestClient(t, http, &Options{
+		MaxRetries: 1,
+		RequestInterceptor: func(req *OutgoingRequest, attempt int, prevErr error) error {
+			attempts = append(attempts, attempt)
+			prevErrs = append(prevErrs, prevErr)
+			req.Headers["X-Signature"] = fmt.Sprintf("sig-%d", attempt)
+			return nil
+		},
+	})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attempts) != 2 || attempts[0] != 0 || attempts[1] != 1 {
+		t.Fatalf("unexpected attempts: %v", attempts)
+	}
+	var apiErr *ApiError
+	if prevErrs[0] != nil || !errors.As(prevErrs[1], &apiErr) || apiErr.Message != "busy" {
+		t.Fatalf("unexpected previous errors: %v", prevErrs)
+	}
+	if http.headers[0]["X-Signature"] != "sig-0" || http.headers[1]["X-Signature"] != "sig-1" {
+		t.Fatalf("expected per-attempt signatures, got %v", http.headers)
+	}
+}
+
+func TestRequestInterceptorErrorAborts(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, &Options{
+		RequestInterceptor: func(*OutgoingRequest, int, error) error { return errors.New("no key") },
+	})
+
+	_, err := client.Ping()
+	var sdkErr *SdkError
+	if !errors.As(err, &sdkErr) || len(http.headers) != 0 {
+		t.Fatalf("expected SdkError without sending, got %v (%d calls)", err, len(http.headers))
+	}
+}
+
+func TestIncludeHostInfoAddsHostnameAndPid(t *testing.T) {
+	restore := hostname
+	defer func() { hostname = restore }()
+	hostname = func() (string, error) { return "worker-1", nil }
+
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{IncludeHostInfo: true})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Progress(ProgressOptions{Fields: map[string]any{"hostname": "custom"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ping, progress map[string]any
+	_ = json.Unmarshal([]byte(http.calls[0].body), &ping)
+	_ = json.Unmarshal([]byte(http.calls[1].body), &progress)
+	if ping["hostname"] != "worker-1" || ping["pid"] != float64(os.Getpid()) {
+		t.Fatalf("unexpected ping body: %v", ping)
+	}
+	if progress["hostname"] != "custom" {
+		t.Fatalf("caller fields should win: %v", progress)
+	}
+
+	hostname = func() (string, error) { return "", errors.New("no hostname") }
+	http = &stubHTTPClient{}
+	client = newTestClient(t, http, &Options{IncludeHostInfo: true})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ping = nil
+	_ = json.Unmarshal([]byte(http.calls[0].body), &ping)
+	if _, ok := ping["hostname"]; ok || ping["pid"] == nil {
+		t.Fatalf("expected pid without hostname, got %v", ping)
+	}
+}
+
+func TestIncludeClientSeq(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 500, body: `{}`}, {status: 200, body: `{}`}}}
+	client := newTestClient(t, http, &Options{IncludeClientSeq: true, MaxRetries: 1})
+
+	_, _ = client.Ping()
+	_, _ = client.Progress(1, "step")
+	_, _ = client.Success()
+
+	want := []string{`{"client_seq":1}`, `{"client_seq":1}`, `{"client_seq":2,"message":"step"}`, `{"client_seq":3}`}
+	for i, call := range http.calls {
+		if call.body != want[i] {
+			t.Fatalf("call %d: expected %s, got %s", i, want[i], call.body)
+		}
+	}
+	if client.ClientSeq() != 3 {
+		t.Fatalf("expected ClientSeq 3, got %d", client.ClientSeq())
+	}
+
+	if other := newTestClient(t, &stubHTTPClient{}, nil); other.ClientSeq() != 0 {
+		t.Fatalf("expected a new client to start at 0")
+	}
+}
+
+func TestAcceptLanguageHeader(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, &Options{AcceptLanguage: "de-DE, en;q=0.5"})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.headers[0]["Accept-Language"]; got != "de-DE, en;q=0.5" {
+		t.Fatalf("unexpected Accept-Language: %q", got)
+	}
+
+	http = &headerCaptureClient{}
+	client = newTestClient(t, http, nil)
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := http.headers[0]["Accept-Language"]; ok {
+		t.Fatalf("expected no Accept-Language header by default")
+	}
+}
+
+func TestRequestContentType(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, &Options{RequestContentType: "application/vnd.cronbeats+json"})
+	if _, err := client.Progress(10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.headers[0]["Content-Type"]; got != "application/vnd.cronbeats+json" {
+		t.Fatalf("unexpected Content-Type: %q", got)
+	}
+	if _, ok := http.headers[1]["Content-Type"]; ok {
+		t.Fatalf("expected no Content-Type without a body, got %v", http.headers[1])
+	}
+
+	http = &headerCaptureClient{}
+	client = newTestClient(t, http, nil)
+	if _, err := client.Progress(10); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.headers[0]["Content-Type"]; got != "application/json" {
+		t.Fatalf("unexpected default Content-Type: %q", got)
+	}
+}
+
+func TestBodyEnvelopeKeyWrapsBody(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{BodyEnvelopeKey: "data", IncludeClientSeq: true})
+	if _, err := client.Progress(10, "halfway"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].body; got != `{"data":{"client_seq":1,"message":"halfway"}}` {
+		t.Fatalf("expected the whole body under the envelope, got %s", got)
+	}
+	if got := http.calls[1].body; got != `{"data":{"client_seq":2}}` {
+		t.Fatalf("expected decorated fields under the envelope, got %s", got)
+	}
+
+	http = &stubHTTPClient{}
+	client = newTestClient(t, http, nil)
+	if _, err := client.Progress(10, "halfway"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := http.calls[0].body; got != `{"message":"halfway"}` {
+		t.Fatalf("expected no envelope by default, got %s", got)
+	}
+}
+
+func TestWithActionInUserAgent(t *testing.T) {
+	http := &headerCaptureClient{}
+	client := newTestClient(t, http, (&Options{UserAgent: "nightly-etl"}).WithActionInUserAgent())
+	_, _ = client.Start()
+	_, _ = client.Progress(nil, "step")
+	_, _ = client.Success()
+	want := []string{"nightly-etl (action=start)", "nightly-etl (action=progress)", "nightly-etl (action=end)"}
+	for i, agent := range want {
+		if got := http.headers[i]["User-Agent"]; got != agent {
+			t.Fatalf("request %d: expected %q, got %q", i, agent, got)
+		}
+	}
+
+	http = &headerCaptureClient{}
+	client = newTestClient(t, http, (&Options{UserAgent: "etl (action=ping)"}).WithActionInUserAgent())
+	_, _ = client.Start()
+	if got := http.headers[0]["User-Agent"]; got != "etl (action=ping)" {
+		t.Fatalf("expected an annotated User-Agent to be left alone, got %q", got)
+	}
+}
+
+func TestWithBuildInfoUserAgent(t *testing.T) {
+	restore := readBuildInfo
+	defer func() { readBuildInfo = restore }()
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Main: debug.Module{Path: "example.com/worker", Version: "v2.3.1"}}, true
+	}
+	if ua := (&Options{}).WithBuildInfoUserAgent().UserAgent; ua != "cronbeats-go-sdk/0.1.0 example.com/worker/v2.3.1" {
+		t.Fatalf("unexpected user agent: %q", ua)
+	}
+	if ua := (&Options{UserAgent: "nightly-etl"}).WithBuildInfoUserAgent().UserAgent; ua != "nightly-etl example.com/worker/v2.3.1" {
+		t.Fatalf("unexpected user agent: %q", ua)
+	}
+
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Main: debug.Module{Path: "example.com/worker", Version: "(devel)"}}, true
+	}
+	if ua := (&Options{}).WithBuildInfoUserAgent().UserAgent; ua != "" {
+		t.Fatalf("expected no build info for a devel build, got %q", ua)
+	}
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return nil, false }
+	if ua := (&Options{UserAgent: "custom"}).WithBuildInfoUserAgent().UserAgent; ua != "custom" {
+		t.Fatalf("expected user agent unchanged without build info, got %q", ua)
+	}
+}
+
+func TestFirstAttemptTimeoutAppliesOnlyToFirstAttempt(t *testing.T) {
+	http := &headerCaptureClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 503, body: `{}`}, {status: 200, body: `{}`}}}
+	client := newTestClient(t, http, &Options{MaxRetries: 2, TimeoutMs: 2000, FirstAttemptTimeoutMs: 8000})
+
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fmt.Sprint(http.timeouts) != "[8000 2000 2000]" {
+		t.Fatalf("unexpected timeouts: %v", http.timeouts)
+	}
+
+	http = &headerCaptureClient{}
+	client = newTestClient(t, http, &Options{TimeoutMs: 2000})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if http.timeouts[0] != 2000 {
+		t.Fatalf("expected TimeoutMs without FirstAttemptTimeoutMs, got %d", http.timeouts[0])
+	}
+}
+
+func TestConfigReturnsResolvedCopy(t *testing.T) {
+	retries := 5
+	client := newTestClient(t, &stubHTTPClient{}, (&Options{
+		TimeoutMs:        7000,
+		FallbackBaseURLs: []string{"https://backup.cronbeats.io/"},
+	}).WithActionOptions("end", ActionOptions{MaxRetries: &retries}))
+
+	cfg := client.Config()
+	if cfg.BaseURL != "https://cronbeats.io" || cfg.TimeoutMs != 7000 || cfg.MaxRetries != 2 || cfg.RetryBackoffMs != 1 {
+		t.Fatalf("unexpected resolved options: %+v", cfg)
+	}
+	if cfg.RetryJitterMs != 100 || cfg.RetryBackoffMultiplier != 2 || cfg.UserAgent != "cronbeats-go-sdk/0.1.0" || cfg.ProgressMaxRunes != 255 {
+		t.Fatalf("unexpected resolved defaults: %+v", cfg)
+	}
+	if cfg.ClockSkewTolerance != time.Minute || cfg.ProgressStreamIntervalMs != 1000 || cfg.BodyPreviewBytes != 512 {
+		t.Fatalf("unexpected resolved defaults: %+v", cfg)
+	}
+
+	cfg.FallbackBaseURLs[0] = "https://mutated.example.com"
+	*cfg.ActionOptions["end"].MaxRetries = 0
+	again := client.Config()
+	if again.FallbackBaseURLs[0] != "https://backup.cronbeats.io/" || *again.ActionOptions["end"].MaxRetries != 5 {
+		t.Fatalf("Config must return a copy, got %+v", again)
+	}
+	if _, err := NewPingClient("abc123de", &again); err != nil {
+		t.Fatalf("resolved options should be valid input: %v", err)
+	}
+}
+
+func TestStrictConfigRejectsContradictions(t *testing.T) {
+	cases := map[string]Options{
+		"ThrottleDrop":          {ThrottleDrop: true},
+		"LatencyPacing.Factor":  {LatencyPacing: LatencyPacingOptions{Factor: 3}},
+		`unknown action "stop"`: *(&Options{}).WithActionOptions("stop", ActionOptions{TimeoutMs: 1}),
+		"FirstAttemptTimeoutMs": {TimeoutMs: 5000, FirstAttemptTimeoutMs: 5000},
+	}
+	for want, opts := range cases {
+		if _, err := NewPingClient("abc123de", &opts); err != nil {
+			t.Fatalf("%s: expected lenient mode to accept, got %v", want, err)
+		}
+		opts.StrictConfig = true
+		_, err := NewPingClient("abc123de", &opts)
+		var vErr *ValidationError
+		if !errors.As(err, &vErr) || !strings.Contains(vErr.Message, want) {
+			t.Fatalf("%s: expected ValidationError mentioning it, got %v", want, err)
+		}
+	}
+
+	if _, err := NewPingClient("abc123de", &Options{StrictConfig: true, RateLimitPerSecond: 2, ThrottleDrop: true}); err != nil {
+		t.Fatalf("unexpected error for consistent options: %v", err)
+	}
+	if _, err := NewPingClient("abc123de", &Options{StrictConfig: true, TimeoutMs: 5000, FirstAttemptTimeoutMs: 1000}); err != nil {
+		t.Fatalf("expected a shorter first attempt to be accepted, got %v", err)
+	}
+}
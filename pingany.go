@@ -0,0 +1,60 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"sync"
+)
+
+// PingAny pings BaseURL and every FallbackBaseURL concurrently, each with its
+// own retries, and returns the first success. The remaining requests are
+// canceled and waited for before PingAny returns. If every endpoint fails,
+// the error is a *MultiError holding each endpoint's error in endpoint order.
+// The request passes the same quiet hours, rate limit, pacing and throttle
+// gates as Ping, and is recorded and queued like one.
+func (c *PingClient) PingAny(ctx context.Context) (*PingSuccess, error) {
+	return c.dispatch(ctx, "ping", route{action: "ping"}, nil, c.sendAny)
+}
+
+// sendAny races send against each endpoint of cfg.
+func (c *PingClient) sendAny(ctx context.Context, cfg *clientConfig, action string, r route, body map[string]any) (*PingSuccess, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type outcome struct {
+		res *PingSuccess
+		err error
+	}
+	outcomes := make([]outcome, len(cfg.endpoints))
+	won := make(chan *PingSuccess, 1)
+	var wg sync.WaitGroup
+	for i, endpoint := range cfg.endpoints {
+		one := *cfg
+		one.endpoints = []string{endpoint}
+		one.weights = nil
+		wg.Add(1)
+		go func(i int, cfg *clientConfig) {
+			defer wg.Done()
+			res, err := c.send(ctx, cfg, action, r, body)
+			outcomes[i] = outcome{res, err}
+			if err == nil {
+				select {
+				case won <- res:
+					cancel()
+				default:
+				}
+			}
+		}(i, &one)
+	}
+	wg.Wait()
+
+	select {
+	case res := <-won:
+		return res, nil
+	default:
+	}
+	errs := make([]error, len(outcomes))
+	for i, o := range outcomes {
+		errs[i] = o.err
+	}
+	return nil, &MultiError{Message: "all endpoints failed", Errors: errs}
+}
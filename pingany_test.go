@@ -0,0 +1,117 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// regionClient answers requests to the primary host only once their context
+// is canceled, and all other hosts immediately with the given status.
+type regionClient struct {
+	status   int
+	canceled atomic.Int32
+}
+
+func (r *regionClient) Request(method string, url string, headers map[string]string, body []byte, timeoutMs int) (*HttpResponse, error) {
+	return r.RequestContext(context.Background(), method, url, headers, body, timeoutMs)
+}
+
+func (r *regionClient) RequestContext(ctx context.Context, _ string, url string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	if strings.HasPrefix(url, "https://cronbeats.io/") {
+		select {
+		case <-ctx.Done():
+			r.canceled.Add(1)
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+	return &HttpResponse{Status: r.status, Body: `{"message":"region down"}`, Headers: map[string]string{}}, nil
+}
+
+func TestPingAnyReturnsFirstSuccess(t *testing.T) {
+	http := &regionClient{status: 200}
+	client := newTestClient(t, http, &Options{FallbackBaseURLs: []string{"https://eu.cronbeats.io", "https://us.cronbeats.io"}})
+
+	started := time.Now()
+	res, err := client.PingAny(context.Background())
+	if err != nil || !res.Ok {
+		t.Fatalf("expected success, got %+v, %v", res, err)
+	}
+	if time.Since(started) > 2*time.Second {
+		t.Fatalf("expected the slow primary to be canceled, took %v", time.Since(started))
+	}
+	if http.canceled.Load() != 1 {
+		t.Fatalf("expected the primary request to be canceled, got %d", http.canceled.Load())
+	}
+	if m := client.Metrics(); m.Requests != 1 || m.Successes != 1 {
+		t.Fatalf("expected one successful request, got %+v", m)
+	}
+}
+
+func TestPingAnyCollectsEndpointErrors(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 404, body: `{"message":"a"}`}, {status: 404, body: `{"message":"b"}`}}}
+	client := newTestClient(t, http, &Options{FallbackBaseURLs: []string{"https://eu.cronbeats.io"}})
+
+	_, err := client.PingAny(context.Background())
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound {
+		t.Fatalf("expected joined ApiErrors, got %v", err)
+	}
+	var multi *MultiError
+	if !errors.As(err, &multi) || len(multi.Errors) != 2 {
+		t.Fatalf("expected one error per endpoint, got %v", err)
+	}
+}
+
+// backoffLoserClient rejects the primary host at once with a long
+// Retry-After and answers the other hosts successfully after a short delay.
+type backoffLoserClient struct{}
+
+func (backoffLoserClient) Request(_ string, url string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	if strings.HasPrefix(url, "https://cronbeats.io/") {
+		return &HttpResponse{Status: 429, Body: `{}`, Headers: map[string]string{"Retry-After": "30"}}, nil
+	}
+	time.Sleep(20 * time.Millisecond)
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestPingAnyCancelsLosersInBackoff(t *testing.T) {
+	client := newTestClient(t, backoffLoserClient{}, &Options{FallbackBaseURLs: []string{"https://eu.cronbeats.io"}, MaxRetries: 3})
+
+	started := time.Now()
+	res, err := client.PingAny(context.Background())
+	if err != nil || !res.Ok {
+		t.Fatalf("expected success, got %+v, %v", res, err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("expected PingAny to return without waiting out the loser's Retry-After, took %v", elapsed)
+	}
+}
+
+func TestPingAnyHonoursQuietHoursAndRateLimit(t *testing.T) {
+	http := &syncStubClient{}
+	client := newTestClient(t, http, &Options{
+		FallbackBaseURLs:   []string{"https://eu.cronbeats.io"},
+		QuietHours:         []TimeRange{{Start: 22 * time.Hour, End: 6 * time.Hour}},
+		RateLimitPerSecond: 1,
+		ThrottleDrop:       true,
+	})
+	current := time.Date(2026, 2, 25, 23, 30, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	if res, err := client.PingAny(context.Background()); err != nil || res.Action != "skipped" || http.count() != 0 {
+		t.Fatalf("expected PingAny to be suppressed in quiet hours, got %+v, %v after %d calls", res, err, http.count())
+	}
+
+	current = time.Date(2026, 2, 26, 12, 0, 0, 0, time.UTC)
+	if _, err := client.PingAny(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := client.PingAny(context.Background()); err != nil || res.Action != "throttled" {
+		t.Fatalf("expected the second PingAny to be throttled, got %+v, %v", res, err)
+	}
+}
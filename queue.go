@@ -0,0 +1,143 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// maxFlusherBackoff bounds how far StartOfflineFlusher stretches its interval
+// while the endpoint stays unreachable.
+const maxFlusherBackoff = 16
+
+// PendingPing is a request that failed with a network error and is held in
+// the offline queue for replay.
+type PendingPing struct {
+	Action     string
+	JobKey     string
+	EnqueuedAt time.Time
+
+	id    uint64
+	route route
+	body  map[string]any
+}
+
+func (c *PingClient) enqueue(cfg *clientConfig, action string, r route, body map[string]any, err error) {
+	var apiErr *ApiError
+	if cfg.queueSize <= 0 || r.batched || !errors.As(err, &apiErr) || apiErr.Code != CodeNetwork {
+		return
+	}
+
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	if len(c.queue) >= cfg.queueSize {
+		c.queue = c.queue[1:]
+	}
+	c.queueSeq++
+	c.queue = append(c.queue, PendingPing{Action: action, JobKey: c.jobKey, EnqueuedAt: c.now(), id: c.queueSeq, route: r, body: body})
+}
+
+// PendingPings returns a snapshot of the offline queue, oldest first.
+func (c *PingClient) PendingPings() []PendingPing {
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	return append([]PendingPing(nil), c.queue...)
+}
+
+// DropPending discards the offline queue and returns how many pings it held.
+func (c *PingClient) DropPending() int {
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	n := len(c.queue)
+	c.queue = nil
+	return n
+}
+
+// FlushPending replays queued pings in order and returns how many were
+// delivered. It stops at the first retryable failure, keeping that ping and
+// the ones after it queued. A ping rejected with a non-retryable error, such
+// as a 4xx, is dropped and reported to the audit log and the EventWriter
+// stream instead, so it cannot block the rest of the queue.
+func (c *PingClient) FlushPending() (int, error) {
+	return c.flushPending(context.Background())
+}
+
+func (c *PingClient) flushPending(ctx context.Context) (int, error) {
+	c.flushMu.Lock()
+	defer c.flushMu.Unlock()
+
+	sent := 0
+	for {
+		c.queueMu.Lock()
+		if len(c.queue) == 0 {
+			c.queueMu.Unlock()
+			return sent, nil
+		}
+		next := c.queue[0]
+		c.queueMu.Unlock()
+
+		cfg := c.config().forAction(next.Action)
+		_, err := c.send(withReplay(withRequestInfo(ctx, c.jobKey, next.Action)), cfg, next.Action, next.route, next.body)
+		c.counters.outcome(err)
+		if err != nil {
+			var apiErr *ApiError
+			if ctx.Err() != nil || !errors.As(err, &apiErr) || apiErr.Retryable {
+				return sent, err
+			}
+			c.audit(cfg, next.Action, err)
+			c.emit(cfg, errorEvent(StreamEvent{Type: EventFailure, Action: next.Action}, err))
+			c.dequeue(next.id)
+			continue
+		}
+		c.audit(cfg, next.Action, nil)
+		c.dequeue(next.id)
+		sent++
+	}
+}
+
+// dequeue removes the ping with the given id if it is still at the head of
+// the queue; DropPending or eviction may have removed it meanwhile.
+func (c *PingClient) dequeue(id uint64) {
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+	if len(c.queue) > 0 && c.queue[0].id == id {
+		c.queue = c.queue[1:]
+	}
+}
+
+// StartOfflineFlusher replays the offline queue every interval until stop is
+// called. While flushing keeps failing the interval is doubled, up to 16
+// times the original, and it is reset after the next successful flush.
+// Calling stop cancels a flush in progress.
+func (c *PingClient) StartOfflineFlusher(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultHeartbeatInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		delay := interval
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.after(delay):
+			}
+			_, err := c.flushPending(ctx)
+			delay = nextFlushDelay(interval, delay, err)
+		}
+	}()
+
+	var once sync.Once
+	return func() { once.Do(cancel) }
+}
+
+func nextFlushDelay(interval time.Duration, current time.Duration, err error) time.Duration {
+	if err == nil {
+		return interval
+	}
+	if next := current * 2; next <= interval*maxFlusherBackoff {
+		return next
+	}
+	return interval * maxFlusherBackoff
+}
@@ -0,0 +1,186 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOfflineQueueBuffersNetworkFailures(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 4}
+	client := newTestClient(t, http, &Options{OfflineQueueSize: 10, MaxRetries: 1})
+	enqueued := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return enqueued }
+
+	if _, err := client.Start(); err == nil {
+		t.Fatal("expected network error")
+	}
+	if _, err := client.Progress(nil, "step"); err == nil {
+		t.Fatal("expected network error")
+	}
+
+	pending := client.PendingPings()
+	if len(pending) != 2 || pending[0].Action != "start" || pending[1].Action != "progress" {
+		t.Fatalf("unexpected pending pings: %#v", pending)
+	}
+	if pending[0].JobKey != "abc123de" || !pending[0].EnqueuedAt.Equal(enqueued) {
+		t.Fatalf("unexpected pending ping: %#v", pending[0])
+	}
+
+	sent, err := client.FlushPending()
+	if err != nil || sent != 2 {
+		t.Fatalf("expected 2 replayed pings, got %d, %v", sent, err)
+	}
+	if len(client.PendingPings()) != 0 {
+		t.Fatal("expected queue to be empty after flush")
+	}
+	last := http.calls[len(http.calls)-1]
+	if last.url != "https://cronbeats.io/ping/abc123de/progress" || last.body != `{"message":"step"}` {
+		t.Fatalf("unexpected replayed call: %#v", last)
+	}
+}
+
+func TestDropPendingDiscardsQueue(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 100}
+	client := newTestClient(t, http, &Options{OfflineQueueSize: 2, MaxRetries: 1})
+
+	_, _ = client.Ping()
+	_, _ = client.Start()
+	_, _ = client.Success()
+
+	pending := client.PendingPings()
+	if len(pending) != 2 || pending[0].Action != "start" || pending[1].Action != "end" {
+		t.Fatalf("expected oldest entry to be evicted, got %#v", pending)
+	}
+	if n := client.DropPending(); n != 2 || len(client.PendingPings()) != 0 {
+		t.Fatalf("expected 2 dropped pings, got %d", n)
+	}
+}
+
+func TestFlushPendingKeepsRemainingOnFailure(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 4}
+	client := newTestClient(t, http, &Options{OfflineQueueSize: 10, MaxRetries: 1})
+
+	_, _ = client.Start()
+	_, _ = client.Success()
+	http.networkFailures = 2
+
+	if sent, err := client.FlushPending(); err == nil || sent != 0 {
+		t.Fatalf("expected flush to fail immediately, got %d, %v", sent, err)
+	}
+	if len(client.PendingPings()) != 2 {
+		t.Fatalf("expected both pings to stay queued, got %d", len(client.PendingPings()))
+	}
+}
+
+func TestFlushPendingDropsNonRetryableFailures(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 4}
+	var audit strings.Builder
+	var out bytes.Buffer
+	client := newTestClient(t, http, &Options{OfflineQueueSize: 10, MaxRetries: 1, AuditWriter: &audit, EventWriter: &out})
+
+	_, _ = client.Start()
+	_, _ = client.Success()
+	http.responses = []stubResponse{{status: 404, body: `{"message":"unknown job"}`}}
+	audit.Reset()
+
+	sent, err := client.FlushPending()
+	if err != nil || sent != 1 {
+		t.Fatalf("expected the 404 to be dropped and the next ping delivered, got %d, %v", sent, err)
+	}
+	if len(client.PendingPings()) != 0 {
+		t.Fatalf("expected the queue to be empty, got %d", len(client.PendingPings()))
+	}
+	if !strings.Contains(audit.String(), "action=start job_key=abc123de outcome=NOT_FOUND http_status=404") {
+		t.Fatalf("expected the dropped ping in the audit log, got %q", audit.String())
+	}
+	if err := client.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var dropped bool
+	for _, e := range decodeEvents(t, out.String()) {
+		if e.Type == EventFailure && e.Action == "start" && e.Code == CodeNotFound {
+			dropped = true
+		}
+	}
+	if !dropped {
+		t.Fatalf("expected a failure event for the dropped ping, got %s", out.String())
+	}
+}
+
+func TestOfflineFlusherReplaysInBackground(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{networkFailures: 2}, &Options{OfflineQueueSize: 10, MaxRetries: 1})
+	if _, err := client.Ping(); err == nil {
+		t.Fatal("expected network error")
+	}
+
+	http := &syncStubClient{}
+	client.cfg.httpClient = http
+	stop := client.StartOfflineFlusher(5 * time.Millisecond)
+	defer stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(client.PendingPings()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("flusher did not drain the queue")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if http.count() != 1 {
+		t.Fatalf("expected 1 replayed request, got %d", http.count())
+	}
+	stop()
+}
+
+func TestNextFlushDelayBacksOffWhileFailing(t *testing.T) {
+	interval := time.Second
+	failed := errors.New("down")
+
+	delay := interval
+	var got []time.Duration
+	for i := 0; i < 6; i++ {
+		delay = nextFlushDelay(interval, delay, failed)
+		got = append(got, delay)
+	}
+	want := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 16 * time.Second, 16 * time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected delays: %v", got)
+		}
+	}
+	if next := nextFlushDelay(interval, delay, nil); next != interval {
+		t.Fatalf("expected delay to reset after success, got %v", next)
+	}
+}
+
+func TestOnSuccessRawSeesLiveAndReplayedBodies(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 2, responses: []stubResponse{
+		{status: 200, body: `{"status":"ok","region":"eu"}`},
+		{status: 200, body: `{"status":"ok","region":"us"}`},
+	}}
+	type seen struct {
+		action   string
+		region   any
+		replayed bool
+	}
+	var calls []seen
+	client := newTestClient(t, http, &Options{OfflineQueueSize: 10, MaxRetries: 1, OnSuccessRaw: func(action string, parsed map[string]any, replayed bool) {
+		calls = append(calls, seen{action, parsed["region"], replayed})
+	}})
+
+	if _, err := client.Start(); err == nil {
+		t.Fatal("expected network error")
+	}
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.FlushPending(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	want := []seen{{"ping", "eu", false}, {"start", "us", true}}
+	if len(calls) != 2 || calls[0] != want[0] || calls[1] != want[1] {
+		t.Fatalf("expected %v, got %v", want, calls)
+	}
+}
@@ -0,0 +1,49 @@
+package cronbeatsgo
+
+import "time"
+
+// TimeRange is a daily window given as offsets from midnight, e.g. Start
+// 22h and End 6h for 22:00 to 06:00. A window whose End is before its Start
+// wraps past midnight. Location defaults to that of the client's clock.
+type TimeRange struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+func (r TimeRange) validate() error {
+	if r.Start < 0 || r.Start >= 24*time.Hour || r.End < 0 || r.End >= 24*time.Hour {
+		return &ValidationError{Message: "QuietHours Start and End must be between 0 and 24h."}
+	}
+	if r.Start == r.End {
+		return &ValidationError{Message: "QuietHours Start and End must differ."}
+	}
+	return nil
+}
+
+// Contains reports whether t falls inside the window. Start is inclusive,
+// End exclusive.
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.Location != nil {
+		t = t.In(r.Location)
+	}
+	y, m, d := t.Date()
+	offset := t.Sub(time.Date(y, m, d, 0, 0, 0, 0, t.Location()))
+	if r.Start < r.End {
+		return offset >= r.Start && offset < r.End
+	}
+	return offset >= r.Start || offset < r.End
+}
+
+// quiet reports whether action is suppressed by QuietHours at now.
+func (cfg *clientConfig) quiet(action string, now time.Time) bool {
+	if action != "ping" && action != "progress" {
+		return false
+	}
+	for _, window := range cfg.quietHours {
+		if window.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
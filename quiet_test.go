@@ -0,0 +1,58 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursSuppressesPingAndProgress(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{QuietHours: []TimeRange{{Start: 22 * time.Hour, End: 6 * time.Hour}}})
+	current := time.Date(2026, 2, 25, 23, 30, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	for _, call := range []func() (*PingSuccess, error){
+		client.Ping,
+		func() (*PingSuccess, error) { return client.Progress(1, "busy") },
+	} {
+		res, err := call()
+		if err != nil || res.Action != "skipped" || res.Ok {
+			t.Fatalf("expected skipped result, got %+v, %v", res, err)
+		}
+	}
+	if _, err := client.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Fail(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 2 || http.calls[0].url != "https://cronbeats.io/ping/abc123de/start" {
+		t.Fatalf("expected only start and fail to be sent, got %+v", http.calls)
+	}
+
+	current = time.Date(2026, 2, 26, 6, 0, 0, 0, time.UTC)
+	if res, err := client.Ping(); err != nil || res.Action == "skipped" {
+		t.Fatalf("expected ping outside quiet hours, got %+v, %v", res, err)
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	day := TimeRange{Start: 9 * time.Hour, End: 17 * time.Hour}
+	if !day.Contains(time.Date(2026, 2, 25, 9, 0, 0, 0, time.UTC)) || day.Contains(time.Date(2026, 2, 25, 17, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected start inclusive and end exclusive")
+	}
+
+	tokyo := time.FixedZone("JST", 9*60*60)
+	local := TimeRange{Start: 0, End: time.Hour, Location: tokyo}
+	if !local.Contains(time.Date(2026, 2, 25, 15, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected 15:30 UTC to be 00:30 in the window's location")
+	}
+
+	for _, bad := range []TimeRange{{Start: time.Hour, End: time.Hour}, {Start: -time.Minute, End: time.Hour}, {Start: 0, End: 24 * time.Hour}} {
+		var vErr *ValidationError
+		if _, err := NewPingClient("abc123de", &Options{QuietHours: []TimeRange{bad}}); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %+v, got %v", bad, err)
+		}
+	}
+}
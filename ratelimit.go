@@ -0,0 +1,190 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// tokenBucket refills at rate tokens per second up to burst. It starts full
+// on first use.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst float64) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: burst}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if b.last.IsZero() {
+		b.tokens = b.burst
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	if now.After(b.last) {
+		b.last = now
+	}
+}
+
+// available reports whether a token could be taken at now, without taking
+// it or otherwise changing the bucket.
+func (b *tokenBucket) available(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last.IsZero() {
+		return true
+	}
+	tokens := b.tokens
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		tokens += elapsed * b.rate
+	}
+	return tokens >= 1
+}
+
+func (b *tokenBucket) take(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// reserve takes a token, going into debt if none is available, and returns
+// how long the caller must wait before its token is valid.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// latencyEWMAWeight is the weight of each new sample in the latency average.
+const latencyEWMAWeight = 0.3
+
+// latencyPacer spaces requests apart when the server slows down. It keeps an
+// exponentially weighted average of response latency; once the average
+// exceeds threshold, a request waits until at least
+// (average - threshold) * factor after the previous response, capped at
+// maxSpacing.
+type latencyPacer struct {
+	mu         sync.Mutex
+	threshold  time.Duration
+	factor     float64
+	maxSpacing time.Duration
+	average    time.Duration
+	last       time.Time
+}
+
+func (p *latencyPacer) observe(latency time.Duration, received time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.last = received
+	if p.average == 0 {
+		p.average = latency
+		return
+	}
+	p.average = time.Duration(latencyEWMAWeight*float64(latency) + (1-latencyEWMAWeight)*float64(p.average))
+}
+
+func (p *latencyPacer) spacing() time.Duration {
+	if p.average <= p.threshold {
+		return 0
+	}
+	gap := time.Duration(float64(p.average-p.threshold) * p.factor)
+	if gap > p.maxSpacing {
+		gap = p.maxSpacing
+	}
+	return gap
+}
+
+// wait returns how long a request starting at now must wait.
+func (p *latencyPacer) wait(now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.last.IsZero() {
+		return 0
+	}
+	if wait := p.last.Add(p.spacing()).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// adaptiveThrottle spaces caller-initiated requests apart while they keep
+// failing with retryable errors. After n consecutive failures a request
+// waits until base * multiplier^(n-1), capped at maxDelay, has passed since
+// the last failure. A success resets it.
+type adaptiveThrottle struct {
+	mu         sync.Mutex
+	base       time.Duration
+	multiplier float64
+	maxDelay   time.Duration
+	failures   int
+	last       time.Time
+}
+
+func (t *adaptiveThrottle) observe(err error, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var apiErr *ApiError
+	if errors.As(err, &apiErr) && apiErr.Retryable {
+		t.failures++
+		t.last = now
+		return
+	}
+	if err == nil {
+		t.failures = 0
+	}
+}
+
+func (t *adaptiveThrottle) level() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.failures
+}
+
+// wait returns how long a request starting at now must wait.
+func (t *adaptiveThrottle) wait(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.failures == 0 {
+		return 0
+	}
+	delay := float64(t.base) * math.Pow(t.multiplier, float64(t.failures-1))
+	if delay > float64(t.maxDelay) {
+		delay = float64(t.maxDelay)
+	}
+	if wait := t.last.Add(time.Duration(delay)).Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// ThrottleLevel returns how many requests in a row have failed with a
+// retryable error while Options.AdaptiveThrottle is enabled; 0 means the
+// client is not throttling.
+func (c *PingClient) ThrottleLevel() int {
+	if throttle := c.config().throttle; throttle != nil {
+		return throttle.level()
+	}
+	return 0
+}
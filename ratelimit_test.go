@@ -0,0 +1,187 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestThrottleDropDiscardsPingsOverLimit(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{RateLimitPerSecond: 1, RateLimitBurst: 2, ThrottleDrop: true})
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	for i := 0; i < 5; i++ {
+		res, err := client.Ping()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if i >= 2 && (res.Ok || res.Action != "throttled") {
+			t.Fatalf("expected ping %d to be throttled, got %#v", i, res)
+		}
+	}
+	if len(http.calls) != 2 || client.DroppedPings() != 3 {
+		t.Fatalf("expected 2 sent and 3 dropped, got %d sent and %d dropped", len(http.calls), client.DroppedPings())
+	}
+
+	current = current.Add(time.Second)
+	if res, _ := client.Ping(); !res.Ok {
+		t.Fatalf("expected ping after refill to be sent, got %#v", res)
+	}
+}
+
+func TestRateLimitBlocksUntilTokenAvailable(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{RateLimitPerSecond: 4})
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	if len(http.calls) != 3 {
+		t.Fatalf("expected blocking limiter to send every ping, got %d", len(http.calls))
+	}
+	if len(waits) != 2 || waits[0] != 250*time.Millisecond || waits[1] != 500*time.Millisecond {
+		t.Fatalf("unexpected limiter waits: %v", waits)
+	}
+}
+
+func TestWouldRateLimitPeeksWithoutTakingToken(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{RateLimitPerSecond: 2, RateLimitBurst: 1})
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return current }
+	client.sleep = func(time.Duration) {}
+
+	for i := 0; i < 3; i++ {
+		if client.WouldRateLimit() {
+			t.Fatalf("expected a full bucket not to limit")
+		}
+	}
+	_, _ = client.Ping()
+	if !client.WouldRateLimit() {
+		t.Fatalf("expected an empty bucket to limit")
+	}
+	current = current.Add(500 * time.Millisecond)
+	if client.WouldRateLimit() {
+		t.Fatalf("expected the refilled token to be available")
+	}
+
+	if newTestClient(t, http, nil).WouldRateLimit() {
+		t.Fatalf("expected no limiting without RateLimitPerSecond")
+	}
+}
+
+type slowClient struct {
+	onRequest func()
+}
+
+func (s *slowClient) Request(_ string, _ string, _ map[string]string, _ []byte, _ int) (*HttpResponse, error) {
+	s.onRequest()
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestLatencyPacingSpacesRequestsWhenServerSlows(t *testing.T) {
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	latency := 100 * time.Millisecond
+	http := &slowClient{onRequest: func() { current = current.Add(latency) }}
+	client := newTestClient(t, http, &Options{LatencyPacing: LatencyPacingOptions{Threshold: 500 * time.Millisecond, Factor: 2}})
+	client.now = func() time.Time { return current }
+
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		current = current.Add(d)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(waits) != 0 {
+		t.Fatalf("expected no pacing while the server is fast, got %v", waits)
+	}
+
+	latency = 2 * time.Second
+	for i := 0; i < 3; i++ {
+		if _, err := client.Ping(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(waits) == 0 {
+		t.Fatalf("expected pacing once latency rose above the threshold")
+	}
+	average := client.cfg.pacer.average
+	if average <= 500*time.Millisecond || client.cfg.pacer.spacing() != 2*(average-500*time.Millisecond) {
+		t.Fatalf("unexpected pacer state: average %v spacing %v", average, client.cfg.pacer.spacing())
+	}
+}
+
+func TestLatencyPacerCapsSpacing(t *testing.T) {
+	p := &latencyPacer{threshold: time.Second, factor: 10, maxSpacing: 5 * time.Second}
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	if wait := p.wait(now); wait != 0 {
+		t.Fatalf("expected first request to go immediately, got %v", wait)
+	}
+	p.observe(10*time.Second, now)
+	if wait := p.wait(now.Add(time.Second)); wait != 4*time.Second {
+		t.Fatalf("expected spacing capped at 5s, got wait %v", wait)
+	}
+}
+
+func TestAdaptiveThrottleSpacesCallsWhileFailing(t *testing.T) {
+	current := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	http := &stubHTTPClient{responses: []stubResponse{
+		{status: 503, body: `{}`},
+		{status: 503, body: `{}`},
+		{status: 503, body: `{}`},
+		{status: 404, body: `{}`},
+		{status: 200, body: `{}`},
+	}}
+	client := newTestClient(t, http, &Options{AdaptiveThrottle: AdaptiveThrottleOptions{BaseDelay: time.Second, MaxDelay: 3 * time.Second}}).With(CallOptions{NoRetry: true})
+	client.now = func() time.Time { return current }
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) {
+		waits = append(waits, d)
+		current = current.Add(d)
+	}
+
+	for i := 0; i < 5; i++ {
+		_, _ = client.Ping()
+	}
+	// The 404 neither counts as a failure nor resets the throttle, so the
+	// last call only waits out what is left of the third failure's delay.
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	if !reflect.DeepEqual(waits, want) {
+		t.Fatalf("expected waits %v, got %v", want, waits)
+	}
+	if client.ThrottleLevel() != 0 {
+		t.Fatalf("expected a success to reset the throttle, got level %d", client.ThrottleLevel())
+	}
+}
+
+func TestAdaptiveThrottleLevelCountsRetryableFailures(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 503, body: `{}`}, {status: 429, body: `{}`}}}
+	client := newTestClient(t, http, &Options{AdaptiveThrottle: AdaptiveThrottleOptions{BaseDelay: time.Millisecond}}).With(CallOptions{NoRetry: true})
+
+	_, _ = client.Ping()
+	_, _ = client.Ping()
+	if client.ThrottleLevel() != 2 {
+		t.Fatalf("expected level 2, got %d", client.ThrottleLevel())
+	}
+	if newTestClient(t, &stubHTTPClient{}, nil).ThrottleLevel() != 0 {
+		t.Fatalf("expected level 0 when disabled")
+	}
+
+	var vErr *ValidationError
+	if _, err := NewPingClient("abc123de", &Options{AdaptiveThrottle: AdaptiveThrottleOptions{BaseDelay: time.Second, Multiplier: 0.5}}); !errors.As(err, &vErr) {
+		t.Fatalf("expected a multiplier below 1 to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readinessCacheTTL is how long ReadinessHandler reuses a Check result.
+const readinessCacheTTL = 5 * time.Second
+
+// Check sends a HEAD request to BaseURL to verify that the CronBeats API is
+// reachable, without pinging the job. A network error or a 5xx response is
+// returned as an *ApiError; any other response counts as reachable.
+func (c *PingClient) Check(ctx context.Context) error {
+	cfg := c.config()
+	headers := map[string]string{"User-Agent": cfg.userAgent}
+	if cfg.dryRun {
+		c.recordDryRun(&OutgoingRequest{Method: "HEAD", URL: cfg.baseURL, Headers: headers})
+		return nil
+	}
+	res, err := sendRequest(withRequestInfo(ctx, c.jobKey, "check"), cfg.httpClient, "HEAD", cfg.baseURL, headers, nil, cfg.timeoutMs)
+	if err != nil {
+		return &ApiError{Code: CodeNetwork, Retryable: true, Message: err.Error(), Raw: err}
+	}
+	if res.Status >= 500 {
+		code, retryable := MapError(res.Status)
+		status := res.Status
+		return &ApiError{Code: code, HTTPStatus: &status, Retryable: retryable, Message: "CronBeats API is unavailable"}
+	}
+	return nil
+}
+
+// ReadinessHandler returns a handler for readiness probes that responds 200
+// when client.Check succeeds and 503 with the error otherwise. Results are
+// reused for a few seconds so frequent probes do not each reach the API.
+func ReadinessHandler(client *PingClient) http.HandlerFunc {
+	var mu sync.Mutex
+	var checkedAt time.Time
+	var last error
+	return func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		if now := client.now(); checkedAt.IsZero() || now.Sub(checkedAt) >= readinessCacheTTL {
+			last = client.Check(r.Context())
+			checkedAt = now
+		}
+		err := last
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		if err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(err.Error() + "\n"))
+			return
+		}
+		_, _ = w.Write([]byte("ok\n"))
+	}
+}
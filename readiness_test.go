@@ -0,0 +1,56 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckReportsUnreachableAPI(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 404}, {status: 503}}}
+	client := newTestClient(t, http, nil)
+
+	if err := client.Check(context.Background()); err != nil {
+		t.Fatalf("expected a 404 to count as reachable, got %v", err)
+	}
+	var apiErr *ApiError
+	if err := client.Check(context.Background()); !errors.As(err, &apiErr) || apiErr.Code != CodeServer {
+		t.Fatalf("expected a server error, got %v", err)
+	}
+	http.networkFailures = 1
+	if err := client.Check(context.Background()); !errors.As(err, &apiErr) || apiErr.Code != CodeNetwork {
+		t.Fatalf("expected a network error, got %v", err)
+	}
+	if len(http.calls) != 3 || http.calls[0].method != "HEAD" || http.calls[0].url != "https://cronbeats.io" {
+		t.Fatalf("expected HEAD requests to the base URL, got %#v", http.calls)
+	}
+}
+
+func TestReadinessHandlerCachesResult(t *testing.T) {
+	http := &stubHTTPClient{networkFailures: 1}
+	client := newTestClient(t, http, nil)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	handler := ReadinessHandler(client)
+
+	probe := func() (int, string) {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest("GET", "/readyz", nil))
+		return rec.Code, rec.Body.String()
+	}
+
+	if code, body := probe(); code != 503 || !strings.Contains(body, "socket timeout") {
+		t.Fatalf("expected 503 with the error, got %d %q", code, body)
+	}
+	now = now.Add(time.Second)
+	if code, _ := probe(); code != 503 || len(http.calls) != 1 {
+		t.Fatalf("expected the cached failure without a new check, got %d after %d calls", code, len(http.calls))
+	}
+	now = now.Add(readinessCacheTTL)
+	if code, body := probe(); code != 200 || body != "ok\n" || len(http.calls) != 2 {
+		t.Fatalf("expected a fresh successful check, got %d %q after %d calls", code, body, len(http.calls))
+	}
+}
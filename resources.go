@@ -0,0 +1,35 @@
+package cronbeatsgo
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+const resourceSampleInterval = time.Second
+
+type resourceSampler struct {
+	mu     sync.Mutex
+	at     time.Time
+	fields map[string]any
+}
+
+// sample returns the process's resource usage, reusing the previous reading
+// when it is less than resourceSampleInterval old.
+func (s *resourceSampler) sample(now time.Time) map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fields != nil && now.Sub(s.at) < resourceSampleInterval && !now.Before(s.at) {
+		return s.fields
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	s.at = now
+	s.fields = map[string]any{
+		"mem_alloc_bytes": mem.HeapAlloc,
+		"mem_sys_bytes":   mem.Sys,
+		"goroutines":      runtime.NumGoroutine(),
+	}
+	return s.fields
+}
@@ -0,0 +1,40 @@
+package cronbeatsgo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestIncludeResourceMetricsAddsStats(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{IncludeResourceMetrics: true})
+	if _, err := client.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var body map[string]any
+	if err := json.Unmarshal([]byte(http.calls[0].body), &body); err != nil {
+		t.Fatalf("unexpected body %q: %v", http.calls[0].body, err)
+	}
+	for _, key := range []string{"mem_alloc_bytes", "mem_sys_bytes", "goroutines"} {
+		if v, ok := body[key].(float64); !ok || v <= 0 {
+			t.Fatalf("expected positive %s, got %v", key, body)
+		}
+	}
+}
+
+func TestResourceSamplerReusesRecentReading(t *testing.T) {
+	var s resourceSampler
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+
+	s.sample(now)
+	s.sample(now.Add(500 * time.Millisecond))
+	if !s.at.Equal(now) {
+		t.Fatalf("expected cached sample within the interval")
+	}
+	s.sample(now.Add(resourceSampleInterval))
+	if !s.at.Equal(now.Add(resourceSampleInterval)) {
+		t.Fatalf("expected a fresh sample after the interval, last at %v", s.at)
+	}
+}
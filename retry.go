@@ -0,0 +1,71 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"time"
+)
+
+// RetryDo calls fn with the client's retry policy: responses are classified
+// as pings are, honouring SuccessStatuses, SuccessDetector,
+// NetworkRetryable, FailFastDNS and ErrorMessageExtractor, and retryable
+// failures are retried up to MaxRetries times with the configured backoff,
+// retry budget and OnRetry hook. CallOptions.NoRetry disables retries. A
+// failed final response is returned together with an *ApiError. ctx is
+// checked before each attempt and ends any backoff early.
+func (c *PingClient) RetryDo(ctx context.Context, fn func() (*HttpResponse, error)) (*HttpResponse, error) {
+	cfg := c.config()
+	if c.call.NoRetry {
+		single := *cfg
+		single.maxRetries = 0
+		cfg = &single
+	}
+	attempt := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		res, err := fn()
+		if err == nil && res == nil {
+			return nil, &SdkError{Message: "RetryDo function returned neither a response nor an error"}
+		}
+		var apiErr *ApiError
+		factor := 1.0
+		var retryAfter time.Duration
+		if err != nil {
+			apiErr = &ApiError{Code: CodeNetwork, Retryable: cfg.retryNetwork(err), Message: err.Error(), Raw: err}
+		} else {
+			parsed := cfg.decodeBody(res.Body)
+			ok, code, retryable := cfg.classify(res, parsed)
+			if ok {
+				return res, nil
+			}
+			msg := cfg.errorMessageOf(parsed)
+			if msg == "" {
+				msg = "Request failed"
+			}
+			status := res.Status
+			apiErr = &ApiError{
+				Code:        code,
+				HTTPStatus:  &status,
+				Retryable:   retryable,
+				Message:     msg,
+				Raw:         parsed,
+				BodyPreview: bodyPreview(string(cfg.redact([]byte(res.Body))), cfg.previewBytes),
+			}
+			factor = backpressureFactor(res.Headers)
+			retryAfter = c.retryAfter(res)
+		}
+
+		if !c.retryAllowed(cfg, apiErr, attempt) {
+			return res, apiErr
+		}
+		attempt++
+		if cfg.onRetry != nil {
+			cfg.onRetry(attempt, retryReason(apiErr.Code), apiErr)
+		}
+		if err := c.sleepWithBackoff(ctx, cfg, attempt, factor, retryAfter, apiErr); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestRetryDoRetriesWithClientPolicy(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{MaxRetries: 3})
+	var reasons []RetryReason
+	client.cfg.onRetry = func(_ int, reason RetryReason, _ error) { reasons = append(reasons, reason) }
+
+	responses := []*HttpResponse{nil, {Status: 429}, {Status: 200, Body: "done"}}
+	calls := 0
+	res, err := client.RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		if responses[calls-1] == nil {
+			return nil, errors.New("connection refused")
+		}
+		return responses[calls-1], nil
+	})
+	if err != nil || res.Body != "done" || calls != 3 {
+		t.Fatalf("expected success on third call, got %v, %v after %d calls", res, err, calls)
+	}
+	if len(reasons) != 2 || reasons[0] != ReasonNetwork || reasons[1] != ReasonRateLimit {
+		t.Fatalf("unexpected retry reasons: %v", reasons)
+	}
+}
+
+func TestRetryDoStopsOnNonRetryableStatus(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{MaxRetries: 3})
+
+	calls := 0
+	res, err := client.RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		return &HttpResponse{Status: 404, Body: "missing"}, nil
+	})
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Code != CodeNotFound || calls != 1 || res == nil || res.Status != 404 {
+		t.Fatalf("expected a single NOT_FOUND attempt, got %v, %v after %d calls", res, err, calls)
+	}
+}
+
+func TestRetryDoHonorsContext(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{MaxRetries: 3})
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	_, err := client.RetryDo(ctx, func() (*HttpResponse, error) {
+		calls++
+		cancel()
+		return &HttpResponse{Status: 503}, nil
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatalf("expected context.Canceled after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryDoUsesClientClassification(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{
+		MaxRetries:            3,
+		SuccessStatuses:       []int{202},
+		FailFastDNS:           true,
+		ErrorMessageExtractor: func(parsed map[string]any) string { s, _ := parsed["error"].(string); return s },
+	})
+
+	calls := 0
+	res, err := client.RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		return &HttpResponse{Status: 202, Body: `{}`}, nil
+	})
+	if err != nil || res.Status != 202 || calls != 1 {
+		t.Fatalf("expected 202 to count as success, got %v, %v after %d calls", res, err, calls)
+	}
+
+	calls = 0
+	_, err = client.RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		return nil, &net.DNSError{Err: "no such host", Name: "example.invalid"}
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected FailFastDNS to stop after one call, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	_, err = client.RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		return &HttpResponse{Status: 200, Body: `{"error":"not accepted"}`}, nil
+	})
+	var apiErr *ApiError
+	if !errors.As(err, &apiErr) || apiErr.Message != "not accepted" || calls != 1 {
+		t.Fatalf("expected 200 outside SuccessStatuses to fail with the extracted message, got %v after %d calls", err, calls)
+	}
+}
+
+func TestRetryDoHonorsNoRetry(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, &Options{MaxRetries: 3})
+
+	calls := 0
+	_, err := client.With(CallOptions{NoRetry: true}).RetryDo(context.Background(), func() (*HttpResponse, error) {
+		calls++
+		return &HttpResponse{Status: 503}, nil
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected a single attempt with NoRetry, got %v after %d calls", err, calls)
+	}
+}
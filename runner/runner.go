@@ -0,0 +1,102 @@
+// Package runner wraps a shell command in CronBeats telemetry. It installs
+// signal handlers while the command runs, so it lives outside the main
+// package for callers that do not want that behaviour.
+package runner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	cronbeatsgo "github.com/cronbeats/cronbeats-go"
+)
+
+var ErrInterrupted = errors.New("command interrupted")
+
+// maxLineBytes is how much of a stdout line is kept for its progress message.
+// A longer line is still echoed in full.
+var maxLineBytes = 4 << 20
+
+// RunOnce starts a run, executes the command and ends the run based on its
+// exit code. Each stdout line is echoed to os.Stdout and reported as a
+// progress message. SIGINT and SIGTERM are forwarded to the command and
+// reported as a failure with reason "interrupted".
+//
+// Telemetry errors are ignored; the returned error is the command's own.
+func RunOnce(ctx context.Context, client *cronbeatsgo.PingClient, name string, args ...string) error {
+	return run(ctx, client, os.Stdout, name, args...)
+}
+
+func run(ctx context.Context, client *cronbeatsgo.PingClient, stdout io.Writer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = os.Stderr
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	_, _ = client.Start()
+	if err := cmd.Start(); err != nil {
+		_, _ = client.FailWithReason(err.Error())
+		return err
+	}
+
+	interrupted := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case sig := <-signals:
+			close(interrupted)
+			_ = cmd.Process.Signal(sig)
+		case <-done:
+		}
+	}()
+
+	reader := bufio.NewReader(pipe)
+	var line []byte
+	for {
+		chunk, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			break
+		}
+		_, _ = stdout.Write(chunk)
+		if room := maxLineBytes - len(line); room > 0 {
+			line = append(line, chunk[:min(len(chunk), room)]...)
+		}
+		if isPrefix {
+			continue
+		}
+		fmt.Fprintln(stdout)
+		_, _ = client.Progress(nil, string(line))
+		line = line[:0]
+	}
+	// Keep draining so the command never blocks on a full pipe.
+	_, _ = io.Copy(stdout, pipe)
+
+	waitErr := cmd.Wait()
+
+	select {
+	case <-interrupted:
+		_, _ = client.FailWithReason("interrupted")
+		return ErrInterrupted
+	default:
+	}
+
+	if waitErr != nil {
+		_, _ = client.FailWithReason(waitErr.Error())
+		return waitErr
+	}
+	_, _ = client.Success()
+	return nil
+}
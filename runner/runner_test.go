@@ -0,0 +1,115 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	cronbeatsgo "github.com/cronbeats/cronbeats-go"
+)
+
+type recordingClient struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recordingClient) Request(_ string, url string, _ map[string]string, body []byte, _ int) (*cronbeatsgo.HttpResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := strings.TrimPrefix(url, "https://cronbeats.io/ping/abc123de")
+	r.calls = append(r.calls, strings.TrimSpace(path+" "+string(body)))
+	return &cronbeatsgo.HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func newClient(t *testing.T, http *recordingClient) *cronbeatsgo.PingClient {
+	t.Helper()
+	client, err := cronbeatsgo.NewPingClient("abc123de", &cronbeatsgo.Options{HTTPClient: http})
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	return client
+}
+
+func TestRunReportsProgressAndSuccess(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	http := &recordingClient{}
+	var out bytes.Buffer
+
+	if err := run(context.Background(), newClient(t, http), &out, "sh", "-c", "echo one; echo two"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"/start",
+		`/progress {"message":"one"}`,
+		`/progress {"message":"two"}`,
+		"/end/success",
+	}
+	if strings.Join(http.calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected calls:\n%s", strings.Join(http.calls, "\n"))
+	}
+	if out.String() != "one\ntwo\n" {
+		t.Fatalf("expected stdout to be echoed, got %q", out.String())
+	}
+}
+
+func TestRunReportsFailureOnExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	http := &recordingClient{}
+
+	err := run(context.Background(), newClient(t, http), &bytes.Buffer{}, "sh", "-c", "exit 3")
+	if err == nil {
+		t.Fatal("expected command error")
+	}
+	last := http.calls[len(http.calls)-1]
+	if last != `/end/fail {"reason":"exit status 3"}` {
+		t.Fatalf("unexpected final call: %s", last)
+	}
+}
+
+func TestRunHandlesLongLines(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	http := &recordingClient{}
+	var out bytes.Buffer
+	script := `head -c 5000000 /dev/zero | tr '\0' x; echo; echo done`
+
+	if err := run(context.Background(), newClient(t, http), &out, "sh", "-c", script); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.Len() != 5000000+len("\ndone\n") || http.calls[len(http.calls)-1] != "/end/success" {
+		t.Fatalf("expected the long line to be echoed and the run to succeed, got %d bytes and %v", out.Len(), http.calls[len(http.calls)-1])
+	}
+	if http.calls[2] != `/progress {"message":"done"}` {
+		t.Fatalf("expected the line after the long one to be reported, got %s", http.calls[2])
+	}
+}
+
+func TestRunTruncatesProgressPastTheLineLimit(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	restore := maxLineBytes
+	maxLineBytes = 4
+	defer func() { maxLineBytes = restore }()
+
+	http := &recordingClient{}
+	var out bytes.Buffer
+	if err := run(context.Background(), newClient(t, http), &out, "sh", "-c", "echo abcdefgh; echo ok"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "abcdefgh\nok\n" {
+		t.Fatalf("expected the full output to be echoed, got %q", out.String())
+	}
+	if http.calls[1] != `/progress {"message":"abcd"}` || http.calls[2] != `/progress {"message":"ok"}` || http.calls[3] != "/end/success" {
+		t.Fatalf("unexpected calls:\n%s", strings.Join(http.calls, "\n"))
+	}
+}
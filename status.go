@@ -0,0 +1,53 @@
+package cronbeatsgo
+
+import "time"
+
+// JobStatus summarizes a job as reported in a ping response. Fields the
+// server did not send are left at their zero value.
+type JobStatus struct {
+	LastPing       *time.Time
+	NextExpected   *time.Time
+	Healthy        bool
+	HealthReason   string
+	RecentFailures int
+	Result         *PingSuccess
+}
+
+// Status pings the job and summarizes the response. Healthy and HealthReason
+// follow the same rules as PingChecked.
+func (c *PingClient) Status() (*JobStatus, error) {
+	res, err := c.Ping()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &JobStatus{Result: res}
+	status.LastPing = rawTime(res.Raw, "last_ping", "timestamp")
+	if res.NextExpected != nil {
+		if next, err := parseTimestamp(*res.NextExpected); err == nil {
+			status.NextExpected = &next
+		}
+	}
+	status.HealthReason = c.unhealthyReason(res)
+	status.Healthy = status.HealthReason == ""
+	for _, key := range []string{"recent_failures", "failure_count"} {
+		if v, ok := res.Raw[key]; ok {
+			status.RecentFailures = int(floatOrZero(v))
+			break
+		}
+	}
+	return status, nil
+}
+
+func rawTime(raw map[string]any, keys ...string) *time.Time {
+	for _, key := range keys {
+		value, ok := raw[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		if t, err := parseTimestamp(value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package cronbeatsgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusSummarizesResponse(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{"status":"success","last_ping":"2026-02-25 11:59:30","next_expected":"2026-02-25 12:05:00","recent_failures":3}`}}}
+	client := newTestClient(t, http, nil)
+	client.now = func() time.Time { return time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC) }
+
+	status, err := client.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.LastPing == nil || !status.LastPing.Equal(time.Date(2026, 2, 25, 11, 59, 30, 0, time.UTC)) {
+		t.Fatalf("unexpected last ping: %v", status.LastPing)
+	}
+	if status.NextExpected == nil || !status.NextExpected.Equal(time.Date(2026, 2, 25, 12, 5, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected next expected: %v", status.NextExpected)
+	}
+	if !status.Healthy || status.HealthReason != "" || status.RecentFailures != 3 {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+}
+
+func TestStatusHandlesMissingFields(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{"overdue":true}`}}}
+	client := newTestClient(t, http, nil)
+
+	status, err := client.Status()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.LastPing != nil || status.NextExpected != nil || status.RecentFailures != 0 {
+		t.Fatalf("expected zero values, got %+v", status)
+	}
+	if status.Healthy || status.HealthReason == "" {
+		t.Fatalf("expected overdue job to be unhealthy: %+v", status)
+	}
+}
@@ -0,0 +1,79 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BeginStep reports that the named phase of the run has started. Steps are
+// sent as progress updates carrying "step" and "step_status"; the name is
+// capped like a progress message.
+func (c *PingClient) BeginStep(name string) (*PingSuccess, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "Step name is required."}
+	}
+
+	fitted, err := c.config().fitMessage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.stepsMu.Lock()
+	if c.steps == nil {
+		c.steps = map[string]time.Time{}
+	}
+	c.steps[name] = c.now()
+	c.stepsMu.Unlock()
+
+	body := map[string]any{"step": fitted, "step_status": "started"}
+	return c.requestContext(context.Background(), "progress", route{action: "progress"}, body)
+}
+
+// EndStep reports that the named step finished with status ("success",
+// "fail" or "canceled"), including its duration when it was begun with
+// BeginStep. Ending a step that was never begun is still reported, with a
+// warning logged.
+func (c *PingClient) EndStep(name string, status string) (*PingSuccess, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Message: "Step name is required."}
+	}
+	statusValue, err := normalizeEndStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	cfg := c.config()
+	fitted, err := cfg.fitMessage(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.stepsMu.Lock()
+	started, open := c.steps[name]
+	delete(c.steps, name)
+	c.stepsMu.Unlock()
+
+	body := map[string]any{"step": fitted, "step_status": statusValue}
+	if open {
+		body["duration_ms"] = float64(c.now().Sub(started)) / float64(time.Millisecond)
+	} else {
+		c.log(cfg, LogEvent{Type: LogEventWarning, JobKey: c.jobKey, Action: "progress", Err: errors.New("step " + name + " ended without BeginStep"), Fields: c.call.LogFields})
+	}
+	return c.requestContext(context.Background(), "progress", route{action: "progress"}, body)
+}
+
+// OpenSteps returns the names of steps begun but not yet ended, sorted.
+func (c *PingClient) OpenSteps() []string {
+	c.stepsMu.Lock()
+	defer c.stepsMu.Unlock()
+	names := make([]string, 0, len(c.steps))
+	for name := range c.steps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
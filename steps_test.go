@@ -0,0 +1,93 @@
+package cronbeatsgo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type eventLogger struct {
+	events []LogEvent
+}
+
+func (l *eventLogger) Log(event LogEvent) {
+	l.events = append(l.events, event)
+}
+
+func TestStepsPostNamedPhases(t *testing.T) {
+	http := &stubHTTPClient{}
+	logger := &eventLogger{}
+	client := newTestClient(t, http, &Options{Logger: logger})
+	now := time.Date(2026, 2, 25, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+
+	if _, err := client.BeginStep("extract"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if open := client.OpenSteps(); len(open) != 1 || open[0] != "extract" {
+		t.Fatalf("unexpected open steps: %v", open)
+	}
+	now = now.Add(1500 * time.Millisecond)
+	if _, err := client.EndStep("extract", "success"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if open := client.OpenSteps(); len(open) != 0 {
+		t.Fatalf("expected no open steps, got %v", open)
+	}
+
+	var begin, end map[string]any
+	_ = json.Unmarshal([]byte(http.calls[0].body), &begin)
+	_ = json.Unmarshal([]byte(http.calls[1].body), &end)
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/progress" || begin["step"] != "extract" || begin["step_status"] != "started" {
+		t.Fatalf("unexpected begin request: %s %v", http.calls[0].url, begin)
+	}
+	if end["step_status"] != "success" || end["duration_ms"] != 1500.0 {
+		t.Fatalf("unexpected end body: %v", end)
+	}
+	for _, event := range logger.events {
+		if event.Type == LogEventWarning {
+			t.Fatalf("unexpected warning: %v", event.Err)
+		}
+	}
+}
+
+func TestEndStepWithoutBeginWarns(t *testing.T) {
+	http := &stubHTTPClient{}
+	logger := &eventLogger{}
+	client := newTestClient(t, http, &Options{Logger: logger})
+
+	if _, err := client.EndStep("load", "fail"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 1 {
+		t.Fatalf("expected the step to be reported anyway, got %d calls", len(http.calls))
+	}
+	if len(logger.events) == 0 || logger.events[0].Type != LogEventWarning {
+		t.Fatalf("expected a warning first, got %#v", logger.events)
+	}
+
+	var vErr *ValidationError
+	if _, err := client.EndStep("load", "maybe"); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for bad status, got %v", err)
+	}
+	if _, err := client.BeginStep(" "); !errors.As(err, &vErr) {
+		t.Fatalf("expected ValidationError for blank name, got %v", err)
+	}
+}
+
+func TestStepNamesHonourOverflowError(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 5, ProgressOverflow: OverflowError})
+
+	var vErr *ValidationError
+	if _, err := client.BeginStep("download"); !errors.As(err, &vErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if _, err := client.EndStep("download", "success"); !errors.As(err, &vErr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(http.calls) != 0 || len(client.OpenSteps()) != 0 {
+		t.Fatalf("expected nothing sent or recorded, got %d calls and %v", len(http.calls), client.OpenSteps())
+	}
+}
@@ -0,0 +1,135 @@
+package cronbeatsgo
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"time"
+)
+
+// maxStreamLineBytes is how much of each line StreamProgress keeps; the rest
+// of a longer line is discarded, as progress messages are capped far below it.
+const maxStreamLineBytes = 64 * 1024
+
+// StreamProgress reads lines from r and reports them as progress messages
+// until EOF or ctx is cancelled. At most one update is sent per
+// ProgressStreamIntervalMs; lines arriving in between are coalesced and only
+// the most recent one is sent. Lines are capped like any progress message,
+// and only their first maxStreamLineBytes are read into memory. Failed
+// updates are skipped. It returns the number of updates the server accepted.
+//
+// If r blocks, the reading goroutine outlives a cancelled ctx until the next
+// Read returns.
+func (c *PingClient) StreamProgress(ctx context.Context, r io.Reader) (int, error) {
+	interval := c.config().streamInterval
+
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(r)
+		for {
+			line, err := readLine(reader, maxStreamLineBytes)
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				scanErr <- err
+				return
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	sent := 0
+	err := c.coalesceProgress(ctx, interval, lines, func(msg string) {
+		if _, err := c.progress(ctx, nil, msg); err == nil {
+			sent++
+		}
+	})
+	if err != nil {
+		return sent, err
+	}
+	return sent, <-scanErr
+}
+
+// readLine returns the next line of r without its line ending, keeping at
+// most max bytes of it and discarding the rest. It returns io.EOF only once
+// no data is left.
+func readLine(r *bufio.Reader, max int) (string, error) {
+	var line []byte
+	read := false
+	for {
+		chunk, isPrefix, err := r.ReadLine()
+		if err != nil {
+			if read {
+				return string(line), nil
+			}
+			return "", err
+		}
+		read = true
+		if room := max - len(line); room > 0 {
+			line = append(line, chunk[:min(len(chunk), room)]...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// DrainProgress reports each message received from ch as a progress update
+// until ch is closed or ctx is cancelled. Updates are coalesced like
+// StreamProgress and numbered with the ProgressIncrement counter, advancing
+// it by one per update sent. It returns the last sequence number reached.
+func (c *PingClient) DrainProgress(ctx context.Context, ch <-chan string) (int, error) {
+	interval := c.config().streamInterval
+	err := c.coalesceProgress(ctx, interval, ch, func(msg string) {
+		_, _ = c.progress(ctx, c.advanceProgress(1), msg)
+	})
+	return c.CurrentProgress(), err
+}
+
+// coalesceProgress calls post with the latest message from lines at most once
+// per interval until lines is closed, flushing the last pending message. It
+// returns ctx.Err() if ctx ends first.
+func (c *PingClient) coalesceProgress(ctx context.Context, interval time.Duration, lines <-chan string, post func(msg string)) error {
+	pending := ""
+	hasPending := false
+	var lastSent time.Time
+	flush := func() {
+		if !hasPending {
+			return
+		}
+		hasPending = false
+		lastSent = c.now()
+		post(pending)
+	}
+
+	tick := c.after(interval)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				flush()
+				return nil
+			}
+			pending = line
+			hasPending = true
+			if lastSent.IsZero() || c.now().Sub(lastSent) >= interval {
+				flush()
+			}
+		case <-tick:
+			tick = c.after(interval)
+			flush()
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cronbeatsgo
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type bodyRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (b *bodyRecorder) Request(_ string, _ string, _ map[string]string, body []byte, _ int) (*HttpResponse, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.bodies = append(b.bodies, string(body))
+	return &HttpResponse{Status: 200, Body: `{}`, Headers: map[string]string{}}, nil
+}
+
+func TestStreamProgressCoalescesBursts(t *testing.T) {
+	http := &bodyRecorder{}
+	client := newTestClient(t, http, &Options{ProgressStreamIntervalMs: 60000})
+
+	var log strings.Builder
+	for i := 0; i < 1000; i++ {
+		log.WriteString("processing row\n")
+	}
+	log.WriteString("done\n")
+
+	sent, err := client.StreamProgress(context.Background(), strings.NewReader(log.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 2 || len(http.bodies) != 2 {
+		t.Fatalf("expected first and last line only, got %d updates: %v", sent, http.bodies)
+	}
+	if http.bodies[0] != `{"message":"processing row"}` || http.bodies[1] != `{"message":"done"}` {
+		t.Fatalf("unexpected updates: %v", http.bodies)
+	}
+}
+
+func TestStreamProgressTruncatesLongLines(t *testing.T) {
+	http := &bodyRecorder{}
+	client := newTestClient(t, http, &Options{ProgressStreamIntervalMs: 60000, ProgressMaxRunes: 10})
+
+	log := strings.Repeat("x", 70*1024) + "\ndone\n"
+	sent, err := client.StreamProgress(context.Background(), strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent != 2 || http.bodies[0] != `{"message":"xxxxxxxxxx"}` || http.bodies[1] != `{"message":"done"}` {
+		t.Fatalf("expected the long line truncated and the next one sent, got %d updates: %v", sent, http.bodies)
+	}
+}
+
+func TestStreamProgressStopsOnCancel(t *testing.T) {
+	client := newTestClient(t, &bodyRecorder{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.StreamProgress(ctx, strings.NewReader("a\nb\n"))
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDrainProgressNumbersCoalescedUpdates(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressStreamIntervalMs: 60000})
+
+	ch := make(chan string, 10)
+	for _, msg := range []string{"batch 1", "batch 2", "batch 3", "finished"} {
+		ch <- msg
+	}
+	close(ch)
+
+	seq, err := client.DrainProgress(context.Background(), ch)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if seq != 2 || len(http.calls) != 2 {
+		t.Fatalf("expected 2 coalesced updates, got seq %d after %d calls", seq, len(http.calls))
+	}
+	if http.calls[0].url != "https://cronbeats.io/ping/abc123de/progress/1" || http.calls[0].body != `{"message":"batch 1"}` {
+		t.Fatalf("unexpected first update: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+	if http.calls[1].url != "https://cronbeats.io/ping/abc123de/progress/2" || http.calls[1].body != `{"message":"finished"}` {
+		t.Fatalf("unexpected last update: %s %s", http.calls[1].url, http.calls[1].body)
+	}
+}
+
+func TestDrainProgressStopsOnCancel(t *testing.T) {
+	client := newTestClient(t, &stubHTTPClient{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.DrainProgress(ctx, make(chan string)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
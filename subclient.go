@@ -0,0 +1,60 @@
+package cronbeatsgo
+
+import (
+	"math/rand"
+)
+
+// SubClient returns a client for childJobKey whose pings are tagged with
+// c's job key as "parent_job_key" and, once c has received one, the run_id
+// of its latest successful response as "parent_run_id", so the dashboard
+// can nest the child's runs under the parent's. The child uses c's options
+// and call options but keeps its own state, counters and offline queue. It
+// shares c's EventWriter stream, so flushing c also flushes its events.
+func (c *PingClient) SubClient(childJobKey string) (*PingClient, error) {
+	if err := ValidateJobKey(childJobKey); err != nil {
+		return nil, err
+	}
+	if childJobKey == c.jobKey {
+		return nil, &ValidationError{Message: "SubClient job key must differ from the parent's."}
+	}
+
+	c.mu.RLock()
+	options := c.options
+	c.mu.RUnlock()
+	options.sharedEvents = c.config().events
+	cfg, err := newClientConfig(options, c.defaultHTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PingClient{
+		clientCore: &clientCore{
+			jobKey:      childJobKey,
+			options:     options,
+			cfg:         cfg,
+			defaultHTTP: c.defaultHTTP,
+			rng:         rand.New(rand.NewSource(c.now().UnixNano())),
+			sleep:       c.sleep,
+			now:         c.now,
+			after:       c.after,
+			parent:      c.clientCore,
+		},
+		call: c.call,
+	}, nil
+}
+
+func (c *clientCore) parentFields() map[string]any {
+	if c.parent == nil {
+		return nil
+	}
+	fields := map[string]any{"parent_job_key": c.parent.jobKey}
+	c.parent.stateMu.Lock()
+	last := c.parent.lastSuccess
+	c.parent.stateMu.Unlock()
+	if last != nil {
+		if runID, ok := last.Raw["run_id"]; ok && runID != nil {
+			fields["parent_run_id"] = runID
+		}
+	}
+	return fields
+}
@@ -0,0 +1,64 @@
+package cronbeatsgo
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSubClientTagsParent(t *testing.T) {
+	http := &stubHTTPClient{responses: []stubResponse{{status: 200, body: `{}`}, {status: 200, body: `{"run_id":"r-42"}`}}}
+	parent := newTestClient(t, http, nil).With(CallOptions{TraceID: "trace-1"})
+
+	child, err := parent.SubClient("child123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := child.Ping(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := parent.Start(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res, err := child.Progress(1, "step"); err != nil || res.TraceID != "trace-1" {
+		t.Fatalf("expected inherited trace ID, got %+v, %v", res, err)
+	}
+
+	if http.calls[0].url != "https://cronbeats.io/ping/child123" || http.calls[0].body != `{"parent_job_key":"abc123de"}` {
+		t.Fatalf("unexpected child ping: %s %s", http.calls[0].url, http.calls[0].body)
+	}
+	if http.calls[2].body != `{"message":"step","parent_job_key":"abc123de","parent_run_id":"r-42"}` {
+		t.Fatalf("unexpected child progress body: %s", http.calls[2].body)
+	}
+	if parent.Metrics().Requests != 1 || child.Metrics().Requests != 2 {
+		t.Fatalf("expected separate counters, got parent %+v child %+v", parent.Metrics(), child.Metrics())
+	}
+}
+
+func TestSubClientValidatesKey(t *testing.T) {
+	parent := newTestClient(t, &stubHTTPClient{}, nil)
+	var vErr *ValidationError
+	for _, key := range []string{"short", "abc123de"} {
+		if _, err := parent.SubClient(key); !errors.As(err, &vErr) {
+			t.Fatalf("expected ValidationError for %q, got %v", key, err)
+		}
+	}
+}
+
+func TestSubClientSharesParentEventStream(t *testing.T) {
+	var out bytes.Buffer
+	parent := newTestClient(t, &stubHTTPClient{}, &Options{EventWriter: &out})
+	child, err := parent.SubClient("child123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, _ = child.Ping()
+	if err := parent.Flush(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	events := decodeEvents(t, out.String())
+	if len(events) != 3 || events[0].JobKey != "child123" {
+		t.Fatalf("expected the parent's flush to write the child's events, got %s", out.String())
+	}
+}
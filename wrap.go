@@ -0,0 +1,63 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Wrap starts a run, calls fn and ends the run with its outcome: success if
+// fn returns nil, otherwise fail with the error as for FailWithError. If a
+// summary function is given, its result for fn's error is sent as the end
+// message, fitted like a progress message, e.g. "processed 1200 records,
+// 3 skipped". If fn panics, the run is ended as failed with reason "panic"
+// and the panic continues.
+//
+// Telemetry errors are ignored; the returned error is fn's own. With
+// OverflowError, a summary or reason over the limit is left out so the run
+// still ends, and its ValidationError is joined to the returned error.
+func (c *PingClient) Wrap(fn func() error, summary ...func(err error) string) error {
+	_, _ = c.Start()
+	// A flag rather than recover, so the panic keeps its original stack.
+	returned := false
+	defer func() {
+		if !returned {
+			_, _ = c.end("fail", map[string]any{"reason": "panic"})
+		}
+	}()
+	err := fn()
+	returned = true
+
+	cfg := c.config()
+	var overflow error
+	body := map[string]any{}
+	if len(summary) > 0 && summary[0] != nil {
+		if msg := strings.TrimSpace(summary[0](err)); msg != "" {
+			if fitted, fitErr := cfg.fitMessage(msg); fitErr != nil {
+				overflow = fitErr
+			} else {
+				body["message"] = fitted
+			}
+		}
+	}
+	status := "success"
+	if err != nil {
+		status = "fail"
+		body["error_type"] = fmt.Sprintf("%T", err)
+		if reason := strings.TrimSpace(err.Error()); reason != "" {
+			if fitted, fitErr := cfg.fitMessage(reason); fitErr != nil {
+				overflow = fitErr
+			} else {
+				body["reason"] = fitted
+			}
+		}
+	}
+	if len(body) == 0 {
+		body = nil
+	}
+	_, _ = c.end(status, body)
+	if overflow != nil {
+		return errors.Join(err, overflow)
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package cronbeatsgo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWrapReportsOutcome(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	if err := client.Wrap(func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(http.calls) != 2 || http.calls[0].url != "https://cronbeats.io/ping/abc123de/start" || http.calls[1].url != "https://cronbeats.io/ping/abc123de/end/success" || http.calls[1].body != "" {
+		t.Fatalf("unexpected calls: %+v", http.calls)
+	}
+
+	jobErr := errors.New("disk full")
+	if err := client.Wrap(func() error { return jobErr }); err != jobErr {
+		t.Fatalf("expected the job's error, got %v", err)
+	}
+	if http.calls[3].url != "https://cronbeats.io/ping/abc123de/end/fail" || http.calls[3].body != `{"error_type":"*errors.errorString","reason":"disk full"}` {
+		t.Fatalf("unexpected fail call: %s %s", http.calls[3].url, http.calls[3].body)
+	}
+}
+
+func TestWrapSendsSummary(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 20})
+
+	summary := func(err error) string {
+		if err != nil {
+			return "gave up"
+		}
+		return fmt.Sprintf("processed %d records, %d skipped", 1200, 3)
+	}
+	_ = client.Wrap(func() error { return nil }, summary)
+	if http.calls[1].body != `{"message":"processed 1200 recor"}` {
+		t.Fatalf("expected truncated summary, got %s", http.calls[1].body)
+	}
+}
+
+func TestWrapOmitsOverflowingSummaryAndReason(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, &Options{ProgressMaxRunes: 5, ProgressOverflow: OverflowError})
+
+	var vErr *ValidationError
+	err := client.Wrap(func() error { return nil }, func(error) string { return "processed 1200 records" })
+	if !errors.As(err, &vErr) || http.calls[1].url != "https://cronbeats.io/ping/abc123de/end/success" || http.calls[1].body != "" {
+		t.Fatalf("expected the run to end without the summary and the overflow returned, got %v, %s %s", err, http.calls[1].url, http.calls[1].body)
+	}
+
+	jobErr := errors.New("disk full")
+	err = client.Wrap(func() error { return jobErr })
+	if !errors.Is(err, jobErr) || !errors.As(err, &vErr) {
+		t.Fatalf("expected the job's error joined with the overflow, got %v", err)
+	}
+	if http.calls[3].url != "https://cronbeats.io/ping/abc123de/end/fail" || http.calls[3].body != `{"error_type":"*errors.errorString"}` {
+		t.Fatalf("expected the fail call without the reason, got %s %s", http.calls[3].url, http.calls[3].body)
+	}
+}
+
+func TestWrapEndsRunOnPanic(t *testing.T) {
+	http := &stubHTTPClient{}
+	client := newTestClient(t, http, nil)
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("expected the panic to continue, got %v", p)
+		}
+		if len(http.calls) != 2 || http.calls[1].url != "https://cronbeats.io/ping/abc123de/end/fail" || http.calls[1].body != `{"reason":"panic"}` {
+			t.Fatalf("expected the run to end as failed, got %+v", http.calls)
+		}
+	}()
+	_ = client.Wrap(func() error { panic("boom") })
+}